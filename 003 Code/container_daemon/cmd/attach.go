@@ -0,0 +1,320 @@
+package cmd
+
+import (
+    "encoding/binary"
+    "fmt"
+    "io"
+    "net"
+    "os"
+    "os/signal"
+    "path/filepath"
+    "strings"
+    "sync"
+    "syscall"
+    "time"
+
+    "github.com/spf13/cobra"
+    "golang.org/x/sys/unix"
+)
+
+const defaultDetachKeys = "ctrl-p,ctrl-q"
+
+// attach 소켓에서 클라이언트가 보내는 메시지 종류
+const (
+    attachFrameStdin  byte = 0 // 표준 입력 데이터
+    attachFrameResize byte = 1 // 터미널 크기 변경 (rows, cols)
+
+    attachMaxFrameSize = 64 * 1024 // 클라이언트가 보낼 수 있는 메시지 본문의 최대 크기
+)
+
+var attachDetachKeys string
+
+var attachCmd = &cobra.Command{
+    Use:   "attach [container]",
+    Short: "Attach to a running container",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        return attachContainer(containerName, attachDetachKeys)
+    },
+}
+
+func init() {
+    attachCmd.Flags().StringVar(&attachDetachKeys, "detach-keys", defaultDetachKeys, "Key sequence for detaching from the container")
+    rootCmd.AddCommand(attachCmd)
+}
+
+// 컨테이너 attach 소켓 경로
+func containerAttachSocket(containerName string) string {
+    return filepath.Join(containerMetaDir(containerName), "attach.sock")
+}
+
+// "ctrl-p,ctrl-q" 같은 표기를 실제 입력 바이트 열로 변환
+func parseDetachKeys(keys string) ([]byte, error) {
+    var seq []byte
+    for _, key := range strings.Split(keys, ",") {
+        key = strings.ToLower(strings.TrimSpace(key))
+        switch {
+        case strings.HasPrefix(key, "ctrl-") && len(key) == 6:
+            c := key[5]
+            switch {
+            case c >= 'a' && c <= 'z':
+                seq = append(seq, c-'a'+1)
+            case c == '@':
+                seq = append(seq, 0)
+            case c >= '[' && c <= '_':
+                seq = append(seq, c-'['+27)
+            default:
+                return nil, fmt.Errorf("invalid detach key %q", key)
+            }
+        case len(key) == 1:
+            seq = append(seq, key[0])
+        default:
+            return nil, fmt.Errorf("invalid detach key %q", key)
+        }
+    }
+    if len(seq) == 0 {
+        return nil, fmt.Errorf("detach key sequence is empty")
+    }
+    return seq, nil
+}
+
+// attachServer : shim이 열어 두는 소켓, 컨테이너 출력을 접속한 클라이언트에 전달하고 입력을 받아 넘김
+type attachServer struct {
+    mu       sync.Mutex
+    listener net.Listener
+    clients  map[net.Conn]struct{}
+    input    io.Writer // 컨테이너 표준 입력 (PTY master 또는 파이프)
+    console  *os.File  // PTY master, 터미널 크기 변경에 사용
+    tty      bool
+}
+
+func newAttachServer(socketPath string, tty bool) (*attachServer, error) {
+    os.Remove(socketPath)
+    listener, err := net.Listen("unix", socketPath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to listen on attach socket: %v", err)
+    }
+    os.Chmod(socketPath, 0600)
+
+    s := &attachServer{listener: listener, clients: map[net.Conn]struct{}{}, tty: tty}
+    go s.serve()
+    return s, nil
+}
+
+func (s *attachServer) setInput(input io.Writer, console *os.File) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.input = input
+    s.console = console
+}
+
+func (s *attachServer) serve() {
+    for {
+        conn, err := s.listener.Accept()
+        if err != nil {
+            return
+        }
+        go s.handle(conn)
+    }
+}
+
+func (s *attachServer) handle(conn net.Conn) {
+    // 첫 바이트로 컨테이너가 TTY 모드인지 알려줌
+    mode := byte('n')
+    if s.tty {
+        mode = 't'
+    }
+    if _, err := conn.Write([]byte{mode}); err != nil {
+        conn.Close()
+        return
+    }
+
+    s.mu.Lock()
+    s.clients[conn] = struct{}{}
+    s.mu.Unlock()
+
+    defer func() {
+        s.mu.Lock()
+        delete(s.clients, conn)
+        s.mu.Unlock()
+        conn.Close()
+    }()
+
+    header := make([]byte, 5)
+    for {
+        if _, err := io.ReadFull(conn, header); err != nil {
+            return
+        }
+        // 길이는 클라이언트가 보낸 값이므로 할당하기 전에 제한
+        size := binary.BigEndian.Uint32(header[1:])
+        if size > attachMaxFrameSize {
+            return
+        }
+        payload := make([]byte, size)
+        if _, err := io.ReadFull(conn, payload); err != nil {
+            return
+        }
+
+        s.mu.Lock()
+        input, console := s.input, s.console
+        s.mu.Unlock()
+
+        switch header[0] {
+        case attachFrameStdin:
+            if input != nil {
+                input.Write(payload)
+            }
+        case attachFrameResize:
+            if console != nil && len(payload) == 4 {
+                ws := &unix.Winsize{Row: binary.BigEndian.Uint16(payload[0:2]), Col: binary.BigEndian.Uint16(payload[2:4])}
+                unix.IoctlSetWinsize(int(console.Fd()), unix.TIOCSWINSZ, ws)
+            }
+        }
+    }
+}
+
+// 컨테이너 출력을 모든 클라이언트에 전달, 느린 클라이언트는 연결을 끊음
+func (s *attachServer) Write(p []byte) (int, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    for conn := range s.clients {
+        conn.SetWriteDeadline(time.Now().Add(time.Second))
+        if _, err := conn.Write(p); err != nil {
+            conn.Close()
+            delete(s.clients, conn)
+        }
+    }
+    return len(p), nil
+}
+
+func (s *attachServer) Close() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    for conn := range s.clients {
+        conn.Close()
+    }
+    return s.listener.Close()
+}
+
+func writeAttachFrame(w io.Writer, frameType byte, payload []byte) error {
+    frame := make([]byte, 5+len(payload))
+    frame[0] = frameType
+    binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+    copy(frame[5:], payload)
+    _, err := w.Write(frame)
+    return err
+}
+
+func sendWinsize(conn net.Conn, term *os.File) {
+    ws, err := unix.IoctlGetWinsize(int(term.Fd()), unix.TIOCGWINSZ)
+    if err != nil {
+        return
+    }
+    payload := make([]byte, 4)
+    binary.BigEndian.PutUint16(payload[0:2], ws.Row)
+    binary.BigEndian.PutUint16(payload[2:4], ws.Col)
+    writeAttachFrame(conn, attachFrameResize, payload)
+}
+
+// 실행 중인 컨테이너의 attach 소켓에 접속하여 터미널을 연결
+func attachContainer(containerName, detachKeys string) error {
+    keys, err := parseDetachKeys(detachKeys)
+    if err != nil {
+        return err
+    }
+
+    conn, err := net.Dial("unix", containerAttachSocket(containerName))
+    if err != nil {
+        return fmt.Errorf("container %s is not running or was not started with -d/-t: %v", containerName, err)
+    }
+    defer conn.Close()
+
+    mode := make([]byte, 1)
+    if _, err := io.ReadFull(conn, mode); err != nil {
+        return fmt.Errorf("failed to attach to container %s: %v", containerName, err)
+    }
+    tty := mode[0] == 't'
+
+    if tty && isTerminal(os.Stdin) {
+        restore, err := setRawTerminal(os.Stdin)
+        if err != nil {
+            return err
+        }
+        defer restore()
+
+        // 터미널 크기를 먼저 보내고, SIGWINCH가 올 때마다 다시 보냄
+        sendWinsize(conn, os.Stdin)
+        sigCh := make(chan os.Signal, 1)
+        signal.Notify(sigCh, syscall.SIGWINCH)
+        defer signal.Stop(sigCh)
+        go func() {
+            for range sigCh {
+                sendWinsize(conn, os.Stdin)
+            }
+        }()
+    }
+
+    outputDone := make(chan struct{})
+    go func() {
+        io.Copy(os.Stdout, conn)
+        close(outputDone)
+    }()
+
+    detached := make(chan struct{})
+    go func() {
+        if copyStdinWithDetach(conn, os.Stdin, keys) {
+            close(detached)
+        }
+    }()
+
+    select {
+    case <-outputDone:
+        // 컨테이너가 종료되어 연결이 끊김
+        return nil
+    case <-detached:
+        conn.Close()
+        fmt.Fprintf(os.Stderr, "\r\nDetached from container %s\r\n", containerName)
+        return nil
+    }
+}
+
+// 표준 입력을 소켓으로 보내다가 detach 키 입력이 완성되면 true 반환
+func copyStdinWithDetach(conn net.Conn, stdin io.Reader, keys []byte) bool {
+    buf := make([]byte, 1024)
+    matched := 0
+    for {
+        n, err := stdin.Read(buf)
+        if n > 0 {
+            var out []byte
+            for _, b := range buf[:n] {
+                if b == keys[matched] {
+                    matched++
+                    if matched == len(keys) {
+                        return true
+                    }
+                    continue
+                }
+                // 일치하다 끊긴 경우 보류했던 키를 그대로 보냄
+                out = append(out, keys[:matched]...)
+                matched = 0
+                if b == keys[0] {
+                    matched = 1
+                    continue
+                }
+                out = append(out, b)
+            }
+            if len(out) > 0 {
+                if err := writeAttachFrame(conn, attachFrameStdin, out); err != nil {
+                    return false
+                }
+            }
+        }
+        if err != nil {
+            return false
+        }
+    }
+}
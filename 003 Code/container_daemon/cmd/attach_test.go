@@ -0,0 +1,48 @@
+package cmd
+
+import (
+    "bytes"
+    "testing"
+)
+
+func TestParseDetachKeys(t *testing.T) {
+    tests := []struct {
+        keys    string
+        want    []byte
+        wantErr bool
+    }{
+        {keys: "ctrl-p,ctrl-q", want: []byte{0x10, 0x11}},
+        {keys: "CTRL-A", want: []byte{0x01}},
+        {keys: "ctrl-a, ctrl-z", want: []byte{0x01, 0x1a}},
+        {keys: "ctrl-@", want: []byte{0x00}},
+        {keys: "ctrl-[", want: []byte{0x1b}},
+        {keys: "ctrl-\\", want: []byte{0x1c}},
+        {keys: "ctrl-_", want: []byte{0x1f}},
+        {keys: "x", want: []byte{'x'}},
+        {keys: "ctrl-p,x", want: []byte{0x10, 'x'}},
+        {keys: "", wantErr: true},
+        {keys: "ctrl-", wantErr: true},
+        {keys: "ctrl-1", wantErr: true},
+        {keys: "ctrl-pq", wantErr: true},
+        {keys: "alt-p", wantErr: true},
+        {keys: "ctrl-p,", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.keys, func(t *testing.T) {
+            got, err := parseDetachKeys(tt.keys)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parseDetachKeys(%q) = %v, want error", tt.keys, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatalf("parseDetachKeys(%q) error = %v", tt.keys, err)
+            }
+            if !bytes.Equal(got, tt.want) {
+                t.Fatalf("parseDetachKeys(%q) = %v, want %v", tt.keys, got, tt.want)
+            }
+        })
+    }
+}
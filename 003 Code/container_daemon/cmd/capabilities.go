@@ -0,0 +1,198 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "sort"
+    "strconv"
+    "strings"
+
+    "golang.org/x/sys/unix"
+)
+
+// 컨테이너 기본 권한 (Docker 기본값에서 CAP_MKNOD를 뺀 최소 집합)
+// 장치 cgroup 제한이 없으므로 CAP_MKNOD가 있으면 호스트 디스크 등의 장치 파일을 만들어 접근할 수 있음
+var defaultCapabilities = []string{
+    "CAP_AUDIT_WRITE",
+    "CAP_CHOWN",
+    "CAP_DAC_OVERRIDE",
+    "CAP_FOWNER",
+    "CAP_FSETID",
+    "CAP_KILL",
+    "CAP_NET_BIND_SERVICE",
+    "CAP_NET_RAW",
+    "CAP_SETFCAP",
+    "CAP_SETGID",
+    "CAP_SETPCAP",
+    "CAP_SETUID",
+    "CAP_SYS_CHROOT",
+}
+
+var capabilityNumbers = map[string]uintptr{
+    "CAP_CHOWN":              unix.CAP_CHOWN,
+    "CAP_DAC_OVERRIDE":       unix.CAP_DAC_OVERRIDE,
+    "CAP_DAC_READ_SEARCH":    unix.CAP_DAC_READ_SEARCH,
+    "CAP_FOWNER":             unix.CAP_FOWNER,
+    "CAP_FSETID":             unix.CAP_FSETID,
+    "CAP_KILL":               unix.CAP_KILL,
+    "CAP_SETGID":             unix.CAP_SETGID,
+    "CAP_SETUID":             unix.CAP_SETUID,
+    "CAP_SETPCAP":            unix.CAP_SETPCAP,
+    "CAP_LINUX_IMMUTABLE":    unix.CAP_LINUX_IMMUTABLE,
+    "CAP_NET_BIND_SERVICE":   unix.CAP_NET_BIND_SERVICE,
+    "CAP_NET_BROADCAST":      unix.CAP_NET_BROADCAST,
+    "CAP_NET_ADMIN":          unix.CAP_NET_ADMIN,
+    "CAP_NET_RAW":            unix.CAP_NET_RAW,
+    "CAP_IPC_LOCK":           unix.CAP_IPC_LOCK,
+    "CAP_IPC_OWNER":          unix.CAP_IPC_OWNER,
+    "CAP_SYS_MODULE":         unix.CAP_SYS_MODULE,
+    "CAP_SYS_RAWIO":          unix.CAP_SYS_RAWIO,
+    "CAP_SYS_CHROOT":         unix.CAP_SYS_CHROOT,
+    "CAP_SYS_PTRACE":         unix.CAP_SYS_PTRACE,
+    "CAP_SYS_PACCT":          unix.CAP_SYS_PACCT,
+    "CAP_SYS_ADMIN":          unix.CAP_SYS_ADMIN,
+    "CAP_SYS_BOOT":           unix.CAP_SYS_BOOT,
+    "CAP_SYS_NICE":           unix.CAP_SYS_NICE,
+    "CAP_SYS_RESOURCE":       unix.CAP_SYS_RESOURCE,
+    "CAP_SYS_TIME":           unix.CAP_SYS_TIME,
+    "CAP_SYS_TTY_CONFIG":     unix.CAP_SYS_TTY_CONFIG,
+    "CAP_MKNOD":              unix.CAP_MKNOD,
+    "CAP_LEASE":              unix.CAP_LEASE,
+    "CAP_AUDIT_WRITE":        unix.CAP_AUDIT_WRITE,
+    "CAP_AUDIT_CONTROL":      unix.CAP_AUDIT_CONTROL,
+    "CAP_SETFCAP":            unix.CAP_SETFCAP,
+    "CAP_MAC_OVERRIDE":       unix.CAP_MAC_OVERRIDE,
+    "CAP_MAC_ADMIN":          unix.CAP_MAC_ADMIN,
+    "CAP_SYSLOG":             unix.CAP_SYSLOG,
+    "CAP_WAKE_ALARM":         unix.CAP_WAKE_ALARM,
+    "CAP_BLOCK_SUSPEND":      unix.CAP_BLOCK_SUSPEND,
+    "CAP_AUDIT_READ":         unix.CAP_AUDIT_READ,
+    "CAP_PERFMON":            unix.CAP_PERFMON,
+    "CAP_BPF":                unix.CAP_BPF,
+    "CAP_CHECKPOINT_RESTORE": unix.CAP_CHECKPOINT_RESTORE,
+}
+
+// "net_admin", "NET_ADMIN", "CAP_NET_ADMIN" 모두 허용
+func normalizeCapability(name string) (string, error) {
+    name = strings.ToUpper(strings.TrimSpace(name))
+    if name == "ALL" {
+        return name, nil
+    }
+    if !strings.HasPrefix(name, "CAP_") {
+        name = "CAP_" + name
+    }
+    if _, ok := capabilityNumbers[name]; !ok {
+        return "", fmt.Errorf("unknown capability %q", name)
+    }
+    return name, nil
+}
+
+// 기본 권한에 --cap-add/--cap-drop을 적용한 최종 권한 목록
+// ALL 지원: --cap-drop ALL --cap-add X 는 X만, --cap-add ALL --cap-drop X 는 X를 뺀 전체
+func resolveCapabilities(add, drop []string) ([]string, error) {
+    normalize := func(names []string) ([]string, error) {
+        var result []string
+        for _, c := range names {
+            name, err := normalizeCapability(c)
+            if err != nil {
+                return nil, err
+            }
+            result = append(result, name)
+        }
+        return result, nil
+    }
+    add, err := normalize(add)
+    if err != nil {
+        return nil, err
+    }
+    drop, err = normalize(drop)
+    if err != nil {
+        return nil, err
+    }
+
+    set := map[string]bool{}
+    for _, c := range defaultCapabilities {
+        set[c] = true
+    }
+    for _, c := range drop {
+        if c == "ALL" {
+            set = map[string]bool{}
+        }
+    }
+    for _, c := range add {
+        if c == "ALL" {
+            for all := range capabilityNumbers {
+                set[all] = true
+            }
+        } else {
+            set[c] = true
+        }
+    }
+    for _, c := range drop {
+        delete(set, c)
+    }
+
+    caps := make([]string, 0, len(set))
+    for c := range set {
+        caps = append(caps, c)
+    }
+    sort.Strings(caps)
+    return caps, nil
+}
+
+// 현재 스레드의 권한을 caps로 제한 (nsinit에서 exec 직전에 호출)
+// bounding 집합에서 나머지를 제거하므로 exec 이후에도 다시 얻을 수 없음
+func dropBoundingCapabilities(caps []string) error {
+    keep := map[uintptr]bool{}
+    for _, c := range caps {
+        keep[capabilityNumbers[c]] = true
+    }
+
+    lastCap := uintptr(unix.CAP_CHECKPOINT_RESTORE)
+    if data, err := os.ReadFile("/proc/sys/kernel/cap_last_cap"); err == nil {
+        if n, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+            lastCap = uintptr(n)
+        }
+    }
+
+    for c := uintptr(0); c <= lastCap; c++ {
+        if keep[c] {
+            continue
+        }
+        if err := unix.Prctl(unix.PR_CAPBSET_DROP, c, 0, 0, 0); err != nil && err != unix.EINVAL {
+            return fmt.Errorf("failed to drop capability %d from bounding set: %v", c, err)
+        }
+    }
+    return nil
+}
+
+// effective/permitted/inheritable 집합을 caps로 설정
+func setProcessCapabilities(caps []string) error {
+    return setCapabilitySets(&specCapabilities{Effective: caps, Permitted: caps, Inheritable: caps})
+}
+
+// 집합별 권한 설정 (OCI 번들의 process.capabilities)
+// ambient는 permitted와 inheritable에 모두 있어야 올릴 수 있으므로 capset 이후에 추가
+func setCapabilitySets(caps *specCapabilities) error {
+    var data [2]unix.CapUserData
+    set := func(names []string, field func(*unix.CapUserData) *uint32) {
+        for _, c := range names {
+            n := capabilityNumbers[c]
+            *field(&data[n/32]) |= 1 << (n % 32)
+        }
+    }
+    set(caps.Effective, func(d *unix.CapUserData) *uint32 { return &d.Effective })
+    set(caps.Permitted, func(d *unix.CapUserData) *uint32 { return &d.Permitted })
+    set(caps.Inheritable, func(d *unix.CapUserData) *uint32 { return &d.Inheritable })
+    header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+    if err := unix.Capset(&header, &data[0]); err != nil {
+        return fmt.Errorf("failed to set capabilities: %v", err)
+    }
+
+    for _, c := range caps.Ambient {
+        if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_RAISE, capabilityNumbers[c], 0, 0); err != nil {
+            return fmt.Errorf("failed to raise ambient capability %s: %v", c, err)
+        }
+    }
+    return nil
+}
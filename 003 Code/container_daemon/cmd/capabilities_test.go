@@ -0,0 +1,85 @@
+package cmd
+
+import (
+    "reflect"
+    "sort"
+    "testing"
+)
+
+func TestResolveCapabilities(t *testing.T) {
+    defaults := append([]string(nil), defaultCapabilities...)
+    sort.Strings(defaults)
+    var all []string
+    for name := range capabilityNumbers {
+        all = append(all, name)
+    }
+    sort.Strings(all)
+    without := func(caps []string, remove ...string) []string {
+        var result []string
+        for _, c := range caps {
+            keep := true
+            for _, r := range remove {
+                keep = keep && c != r
+            }
+            if keep {
+                result = append(result, c)
+            }
+        }
+        return result
+    }
+    with := func(caps []string, add ...string) []string {
+        result := append(append([]string(nil), caps...), add...)
+        sort.Strings(result)
+        return result
+    }
+
+    tests := []struct {
+        name    string
+        add     []string
+        drop    []string
+        want    []string
+        wantErr bool
+    }{
+        {name: "defaults", want: defaults},
+        {name: "add", add: []string{"CAP_NET_ADMIN"}, want: with(defaults, "CAP_NET_ADMIN")},
+        {name: "add without prefix in lower case", add: []string{"sys_ptrace"}, want: with(defaults, "CAP_SYS_PTRACE")},
+        {name: "add existing", add: []string{"chown"}, want: defaults},
+        {name: "drop", drop: []string{"NET_RAW", "cap_kill"}, want: without(defaults, "CAP_NET_RAW", "CAP_KILL")},
+        {name: "drop all then add", add: []string{"CHOWN"}, drop: []string{"all"}, want: []string{"CAP_CHOWN"}},
+        {name: "drop all", drop: []string{"ALL"}, want: []string{}},
+        {name: "add all then drop", add: []string{"ALL"}, drop: []string{"SYS_ADMIN"}, want: without(all, "CAP_SYS_ADMIN")},
+        {name: "drop wins over add", add: []string{"MKNOD"}, drop: []string{"MKNOD"}, want: defaults},
+        {name: "unknown add", add: []string{"CAP_FLY"}, wantErr: true},
+        {name: "unknown drop", drop: []string{"FLY"}, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := resolveCapabilities(tt.add, tt.drop)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("resolveCapabilities(%v, %v) = %v, want error", tt.add, tt.drop, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("resolveCapabilities(%v, %v) = %v, want %v", tt.add, tt.drop, got, tt.want)
+            }
+        })
+    }
+}
+
+// 장치 cgroup 제한이 없으므로 기본 권한에는 CAP_MKNOD가 없어야 함
+func TestDefaultCapabilitiesExcludeMknod(t *testing.T) {
+    for _, c := range defaultCapabilities {
+        if _, ok := capabilityNumbers[c]; !ok {
+            t.Errorf("default capability %s is unknown", c)
+        }
+        if c == "CAP_MKNOD" {
+            t.Errorf("default capabilities include CAP_MKNOD")
+        }
+    }
+}
@@ -0,0 +1,325 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "syscall"
+    "time"
+
+    "golang.org/x/sys/unix"
+)
+
+const (
+    cgroupRoot      = "/sys/fs/cgroup"
+    carteSlice      = "carte.slice"
+    cpuPeriodMicros = 100000 // cpu.max 주기 (100ms)
+)
+
+// containerResources : 컨테이너 cgroup에 적용할 자원 제한 (0이면 제한 없음)
+type containerResources struct {
+    CPUs       float64 `json:"cpus,omitempty"`
+    Memory     int64   `json:"memory,omitempty"`
+    MemorySwap int64   `json:"memorySwap,omitempty"` // 메모리+스왑 합계, -1이면 스왑 무제한
+    PidsLimit  int64   `json:"pidsLimit,omitempty"`
+    CPUShares  int64   `json:"cpuShares,omitempty"`
+    IOWeight   int64   `json:"ioWeight,omitempty"`
+}
+
+// CLI 플래그 값으로 자원 제한 구성
+func parseResources(cpus, memory, memorySwap string, pidsLimit, cpuShares, ioWeight int64) (containerResources, error) {
+    var res containerResources
+
+    if cpus != "" {
+        n, err := strconv.ParseFloat(cpus, 64)
+        if err != nil || n <= 0 {
+            return res, fmt.Errorf("invalid --cpus value %q", cpus)
+        }
+        res.CPUs = n
+    }
+    if memory != "" {
+        n, err := parseSize(memory)
+        if err != nil {
+            return res, fmt.Errorf("invalid --memory value: %v", err)
+        }
+        res.Memory = n
+    }
+    if memorySwap != "" {
+        if memorySwap == "-1" {
+            res.MemorySwap = -1
+        } else {
+            n, err := parseSize(memorySwap)
+            if err != nil {
+                return res, fmt.Errorf("invalid --memory-swap value: %v", err)
+            }
+            if res.Memory == 0 {
+                return res, fmt.Errorf("--memory-swap requires --memory")
+            }
+            if n < res.Memory {
+                return res, fmt.Errorf("--memory-swap must be larger than or equal to --memory")
+            }
+            res.MemorySwap = n
+        }
+    }
+    if pidsLimit < 0 {
+        return res, fmt.Errorf("invalid --pids-limit value %d", pidsLimit)
+    }
+    res.PidsLimit = pidsLimit
+    if cpuShares != 0 && (cpuShares < 2 || cpuShares > 262144) {
+        return res, fmt.Errorf("--cpu-shares must be between 2 and 262144")
+    }
+    res.CPUShares = cpuShares
+    if ioWeight != 0 && (ioWeight < 1 || ioWeight > 10000) {
+        return res, fmt.Errorf("--io-weight must be between 1 and 10000")
+    }
+    res.IOWeight = ioWeight
+
+    return res, nil
+}
+
+// 컨테이너 cgroup 경로 (/sys/fs/cgroup/carte.slice/<containerName>)
+func containerCgroupPath(containerName string) string {
+    return filepath.Join(cgroupRoot, carteSlice, containerName)
+}
+
+// cgroup v2 파일시스템이 마운트되어 있는지 확인
+func checkCgroupV2() error {
+    var st unix.Statfs_t
+    if err := unix.Statfs(cgroupRoot, &st); err != nil {
+        return fmt.Errorf("failed to stat %s: %v", cgroupRoot, err)
+    }
+    if st.Type != unix.CGROUP2_SUPER_MAGIC {
+        return fmt.Errorf("%s is not a cgroup v2 filesystem", cgroupRoot)
+    }
+    return nil
+}
+
+// 상위 cgroup에서 사용 가능한 컨트롤러를 하위 cgroup에 위임
+func enableControllers(dir string, wanted []string) error {
+    data, err := os.ReadFile(filepath.Join(dir, "cgroup.controllers"))
+    if err != nil {
+        return fmt.Errorf("failed to read controllers of %s: %v", dir, err)
+    }
+    available := strings.Fields(string(data))
+
+    var enable []string
+    for _, controller := range wanted {
+        for _, a := range available {
+            if a == controller {
+                enable = append(enable, "+"+controller)
+            }
+        }
+    }
+    if len(enable) == 0 {
+        return nil
+    }
+    if err := os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte(strings.Join(enable, " ")), 0644); err != nil {
+        return fmt.Errorf("failed to enable controllers in %s: %v", dir, err)
+    }
+    return nil
+}
+
+// 컨테이너 cgroup을 만들고 자원 제한을 적용, 반환된 디렉토리 fd로 자식 프로세스를 clone 시점에 cgroup에 넣음
+func setupCgroups(containerName string, res containerResources) (*os.File, error) {
+    return setupCgroupPath(containerCgroupPath(containerName), res)
+}
+
+// cgroupPath(cgroupRoot 아래)까지 상위 cgroup마다 컨트롤러를 위임하고 자원 제한 적용
+func setupCgroupPath(cgroupPath string, res containerResources) (*os.File, error) {
+    if err := checkCgroupV2(); err != nil {
+        return nil, err
+    }
+    limits, err := cgroupLimits(res)
+    if err != nil {
+        return nil, err
+    }
+
+    controllers := []string{"cpu", "memory", "pids", "io"}
+    rel, err := filepath.Rel(cgroupRoot, filepath.Dir(cgroupPath))
+    if err != nil || strings.HasPrefix(rel, "..") {
+        return nil, fmt.Errorf("cgroup %s is not under %s", cgroupPath, cgroupRoot)
+    }
+    ancestor := cgroupRoot
+    if err := enableControllers(ancestor, controllers); err != nil {
+        return nil, err
+    }
+    if rel != "." {
+        for _, part := range strings.Split(rel, "/") {
+            ancestor = filepath.Join(ancestor, part)
+            if err := os.Mkdir(ancestor, 0755); err != nil && !os.IsExist(err) {
+                return nil, fmt.Errorf("failed to create %s: %v", ancestor, err)
+            }
+            if err := enableControllers(ancestor, controllers); err != nil {
+                return nil, err
+            }
+        }
+    }
+
+    if err := os.Mkdir(cgroupPath, 0755); err != nil && !os.IsExist(err) {
+        return nil, fmt.Errorf("failed to create cgroup %s: %v", cgroupPath, err)
+    }
+
+    for file, value := range limits {
+        if err := os.WriteFile(filepath.Join(cgroupPath, file), []byte(value), 0644); err != nil {
+            // 제한을 요청하지 않은 항목은 컨트롤러가 없어도 무시
+            if os.IsNotExist(err) && !resourceRequested(file, res) {
+                continue
+            }
+            return nil, fmt.Errorf("failed to set %s: %v", file, err)
+        }
+    }
+
+    dir, err := os.Open(cgroupPath)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open cgroup %s: %v", cgroupPath, err)
+    }
+    return dir, nil
+}
+
+// 자원 제한을 cgroup 파일 값으로 변환
+// cgroup 디렉토리는 다시 시작할 때 재사용되므로 요청하지 않은 항목도 기본값으로 되돌림
+func cgroupLimits(res containerResources) (map[string]string, error) {
+    if res.MemorySwap > 0 && res.Memory == 0 {
+        return nil, fmt.Errorf("memory swap limit requires a memory limit")
+    }
+
+    limits := map[string]string{}
+    if res.CPUs > 0 {
+        limits["cpu.max"] = fmt.Sprintf("%d %d", int64(res.CPUs*cpuPeriodMicros), cpuPeriodMicros)
+    } else {
+        limits["cpu.max"] = fmt.Sprintf("max %d", cpuPeriodMicros)
+    }
+    limits["cpu.weight"] = "100"
+    if res.CPUShares > 0 {
+        // cgroup v1 shares(2~262144)를 v2 weight(1~10000)로 변환
+        limits["cpu.weight"] = strconv.FormatInt(1+((res.CPUShares-2)*9999)/262142, 10)
+    }
+    limits["memory.max"] = "max"
+    if res.Memory > 0 {
+        limits["memory.max"] = strconv.FormatInt(res.Memory, 10)
+    }
+    switch {
+    case res.MemorySwap == -1 || res.Memory == 0:
+        limits["memory.swap.max"] = "max"
+    case res.MemorySwap > 0:
+        limits["memory.swap.max"] = strconv.FormatInt(res.MemorySwap-res.Memory, 10)
+    default:
+        // Docker와 같이 --memory만 주면 메모리+스왑 합계는 메모리의 2배
+        limits["memory.swap.max"] = strconv.FormatInt(res.Memory, 10)
+    }
+    limits["pids.max"] = "max"
+    if res.PidsLimit > 0 {
+        limits["pids.max"] = strconv.FormatInt(res.PidsLimit, 10)
+    }
+    limits["io.weight"] = "default 100"
+    if res.IOWeight > 0 {
+        limits["io.weight"] = fmt.Sprintf("default %d", res.IOWeight)
+    }
+    return limits, nil
+}
+
+func resourceRequested(file string, res containerResources) bool {
+    switch file {
+    case "cpu.max":
+        return res.CPUs > 0
+    case "cpu.weight":
+        return res.CPUShares > 0
+    case "memory.max":
+        return res.Memory > 0
+    case "memory.swap.max":
+        return res.MemorySwap != 0
+    case "pids.max":
+        return res.PidsLimit > 0
+    case "io.weight":
+        return res.IOWeight > 0
+    }
+    return false
+}
+
+// 컨테이너 cgroup 삭제 (안에 프로세스가 남아 있으면 실패)
+func removeCgroup(containerName string) error {
+    return removeCgroupPath(containerCgroupPath(containerName))
+}
+
+func removeCgroupPath(cgroupPath string) error {
+    if err := syscall.Rmdir(cgroupPath); err != nil && err != syscall.ENOENT {
+        return fmt.Errorf("failed to remove cgroup %s: %v", cgroupPath, err)
+    }
+    return nil
+}
+
+// cgroup 안의 모든 프로세스에 SIGKILL (cgroup.kill이 없는 커널은 cgroup.procs를 돌며 직접 보냄)
+func killCgroup(containerName string) error {
+    return killCgroupPath(containerCgroupPath(containerName))
+}
+
+func killCgroupPath(cgroupPath string) error {
+    err := os.WriteFile(filepath.Join(cgroupPath, "cgroup.kill"), []byte("1"), 0644)
+    if err == nil || os.IsNotExist(err) && !cgroupPathExists(cgroupPath) {
+        return nil
+    }
+
+    procs, err := readCgroupPathProcs(cgroupPath)
+    if err != nil {
+        return err
+    }
+    for _, pid := range procs {
+        syscall.Kill(pid, syscall.SIGKILL)
+    }
+    return nil
+}
+
+func cgroupExists(containerName string) bool {
+    return cgroupPathExists(containerCgroupPath(containerName))
+}
+
+func cgroupPathExists(cgroupPath string) bool {
+    _, err := os.Stat(cgroupPath)
+    return err == nil
+}
+
+// 컨테이너 cgroup에 속한 프로세스의 호스트 PID 목록
+func readCgroupProcs(containerName string) ([]int, error) {
+    return readCgroupPathProcs(containerCgroupPath(containerName))
+}
+
+func readCgroupPathProcs(cgroupPath string) ([]int, error) {
+    data, err := os.ReadFile(filepath.Join(cgroupPath, "cgroup.procs"))
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to read processes of cgroup %s: %v", cgroupPath, err)
+    }
+    var pids []int
+    for _, field := range strings.Fields(string(data)) {
+        if pid, err := strconv.Atoi(field); err == nil {
+            pids = append(pids, pid)
+        }
+    }
+    return pids, nil
+}
+
+// cgroup.events의 populated가 0이 될 때까지 대기 (남은 프로세스가 없을 때)
+func waitCgroupEmpty(containerName string, timeout time.Duration) error {
+    return waitCgroupPathEmpty(containerCgroupPath(containerName), timeout)
+}
+
+func waitCgroupPathEmpty(cgroupPath string, timeout time.Duration) error {
+    eventsPath := filepath.Join(cgroupPath, "cgroup.events")
+    deadline := time.Now().Add(timeout)
+    for {
+        events, err := readKeyValueFile(eventsPath)
+        if err != nil || events["populated"] == 0 {
+            return nil
+        }
+        if time.Now().After(deadline) {
+            return fmt.Errorf("processes in cgroup %s are still running after SIGKILL", cgroupPath)
+        }
+        // cgroup.kill이 없는 커널에서는 그 사이 fork된 프로세스도 다시 종료
+        killCgroupPath(cgroupPath)
+        time.Sleep(100 * time.Millisecond)
+    }
+}
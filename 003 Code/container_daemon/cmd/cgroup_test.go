@@ -0,0 +1,122 @@
+package cmd
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestParseResources(t *testing.T) {
+    tests := []struct {
+        name                      string
+        cpus, memory, memorySwap  string
+        pids, cpuShares, ioWeight int64
+        want                      containerResources
+        wantErr                   bool
+    }{
+        {name: "none", want: containerResources{}},
+        {name: "cpus", cpus: "0.5", want: containerResources{CPUs: 0.5}},
+        {name: "memory", memory: "512m", want: containerResources{Memory: 512 << 20}},
+        {name: "memory and swap", memory: "512m", memorySwap: "1g", want: containerResources{Memory: 512 << 20, MemorySwap: 1 << 30}},
+        {name: "unlimited swap", memory: "512m", memorySwap: "-1", want: containerResources{Memory: 512 << 20, MemorySwap: -1}},
+        {name: "weights", pids: 100, cpuShares: 512, ioWeight: 500, want: containerResources{PidsLimit: 100, CPUShares: 512, IOWeight: 500}},
+        {name: "zero cpus", cpus: "0", wantErr: true},
+        {name: "invalid cpus", cpus: "two", wantErr: true},
+        {name: "invalid memory", memory: "lots", wantErr: true},
+        {name: "swap without memory", memorySwap: "1g", wantErr: true},
+        {name: "swap smaller than memory", memory: "1g", memorySwap: "512m", wantErr: true},
+        {name: "negative pids", pids: -1, wantErr: true},
+        {name: "cpu shares too small", cpuShares: 1, wantErr: true},
+        {name: "cpu shares too large", cpuShares: 262145, wantErr: true},
+        {name: "io weight too large", ioWeight: 10001, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := parseResources(tt.cpus, tt.memory, tt.memorySwap, tt.pids, tt.cpuShares, tt.ioWeight)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parseResources() = %+v, want error", got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != tt.want {
+                t.Fatalf("parseResources() = %+v, want %+v", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestCgroupLimits(t *testing.T) {
+    // 요청하지 않은 항목도 기본값으로 기록해야 이전 실행의 제한이 남지 않음
+    defaults := map[string]string{
+        "cpu.max":         "max 100000",
+        "cpu.weight":      "100",
+        "memory.max":      "max",
+        "memory.swap.max": "max",
+        "pids.max":        "max",
+        "io.weight":       "default 100",
+    }
+    with := func(changes map[string]string) map[string]string {
+        limits := map[string]string{}
+        for file, value := range defaults {
+            limits[file] = value
+        }
+        for file, value := range changes {
+            limits[file] = value
+        }
+        return limits
+    }
+
+    tests := []struct {
+        name    string
+        res     containerResources
+        want    map[string]string
+        wantErr bool
+    }{
+        {name: "defaults", want: defaults},
+        {name: "cpus", res: containerResources{CPUs: 1.5}, want: with(map[string]string{"cpu.max": "150000 100000"})},
+        {name: "minimum cpu shares", res: containerResources{CPUShares: 2}, want: with(map[string]string{"cpu.weight": "1"})},
+        {name: "default cpu shares", res: containerResources{CPUShares: 1024}, want: with(map[string]string{"cpu.weight": "39"})},
+        {name: "maximum cpu shares", res: containerResources{CPUShares: 262144}, want: with(map[string]string{"cpu.weight": "10000"})},
+        {
+            name: "memory only allows the same amount of swap",
+            res:  containerResources{Memory: 256 << 20},
+            want: with(map[string]string{"memory.max": "268435456", "memory.swap.max": "268435456"}),
+        },
+        {
+            name: "memory and swap total",
+            res:  containerResources{Memory: 256 << 20, MemorySwap: 1 << 30},
+            want: with(map[string]string{"memory.max": "268435456", "memory.swap.max": "805306368"}),
+        },
+        {
+            name: "memory with unlimited swap",
+            res:  containerResources{Memory: 256 << 20, MemorySwap: -1},
+            want: with(map[string]string{"memory.max": "268435456"}),
+        },
+        {name: "unlimited swap without memory", res: containerResources{MemorySwap: -1}, want: defaults},
+        {name: "pids", res: containerResources{PidsLimit: 64}, want: with(map[string]string{"pids.max": "64"})},
+        {name: "io weight", res: containerResources{IOWeight: 500}, want: with(map[string]string{"io.weight": "default 500"})},
+        {name: "swap without memory", res: containerResources{MemorySwap: 1 << 30}, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := cgroupLimits(tt.res)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("cgroupLimits(%+v) = %v, want error", tt.res, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("cgroupLimits(%+v) = %v, want %v", tt.res, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+    "path/filepath"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+var (
+    commitChanges []string
+    commitMessage string
+    commitPause   bool
+)
+
+var commitCmd = &cobra.Command{
+    Use:   "commit [container] [name:tag]",
+    Short: "Create a new image from a container's changes",
+    Args:  cobra.ExactArgs(2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        return commitContainer(containerName, args[1])
+    },
+}
+
+func init() {
+    commitCmd.Flags().StringArrayVarP(&commitChanges, "change", "c", nil, "Apply a Dockerfile instruction to the image config (CMD, ENTRYPOINT, ENV, WORKDIR, USER, EXPOSE, STOPSIGNAL)")
+    commitCmd.Flags().StringVarP(&commitMessage, "message", "m", "", "Commit message")
+    commitCmd.Flags().BoolVarP(&commitPause, "pause", "p", true, "Pause the container while committing")
+    rootCmd.AddCommand(commitCmd)
+}
+
+// 컨테이너가 만들어진 이미지와 현재 파일시스템의 차이를 새 레이어로 묶어 이미지로 등록
+func commitContainer(containerName, ref string) error {
+    name, tag, err := parseImageRef(ref)
+    if err != nil {
+        return err
+    }
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return err
+    }
+    base, parent, err := containerBaseManifest(containerName)
+    if err != nil {
+        return err
+    }
+
+    // 실행 설정: 부모 이미지 설정 (없으면 컨테이너의 이미지 설정)에 --change 적용
+    // 부모 이미지 기록은 그대로 두어야 하므로 복사본에 적용
+    config := &imageConfig{}
+    if parent != nil {
+        copied := copyImageConfig(parent.Config)
+        config = &copied
+    } else if config, err = loadContainerImageConfig(containerName); err != nil {
+        return err
+    }
+    for _, change := range commitChanges {
+        if err := applyImageChange(config, change); err != nil {
+            return err
+        }
+    }
+
+    // 실행 중이면 파일을 읽는 동안 바뀌지 않도록 멈춤
+    if state.Status == statusRunning && commitPause {
+        if err := freezeCgroup(containerName, true); err != nil {
+            return err
+        }
+        defer freezeCgroup(containerName, false)
+    }
+
+    root := filepath.Join("/CarteDaemon/container", containerName)
+    changes, err := computeChanges(root, base)
+    if err != nil {
+        return err
+    }
+    layer, err := storeLayer(func(w io.Writer) error {
+        return writeLayerTar(w, root, changes)
+    })
+    if err != nil {
+        return err
+    }
+
+    img := &imageRecord{
+        Container: state.ID,
+        Comment:   commitMessage,
+        Created:   time.Now().UTC(),
+        Config:    *config,
+    }
+    if parent != nil {
+        img.Parent = parent.ID
+        img.Layers = append(img.Layers, parent.Layers...)
+    }
+    img.Layers = append(img.Layers, layer)
+    if err := registerImage(img, name+":"+tag); err != nil {
+        return err
+    }
+    recordEvent(lifecycleEvent{Type: "image", Action: "commit", ID: img.ID, Name: name + ":" + tag, Attributes: map[string]string{"container": img.Container}})
+
+    fmt.Printf("Committed %d changes of container %s as %s:%s\n", len(changes), state.Name, name, tag)
+    fmt.Println("sha256:" + img.ID)
+    return nil
+}
+
+// Dockerfile 형식 명령 하나를 이미지 설정에 반영 (예: CMD ["nginx", "-g", "daemon off;"], ENV A=1 B=2)
+func applyImageChange(config *imageConfig, change string) error {
+    instruction, value, _ := strings.Cut(strings.TrimSpace(change), " ")
+    value = strings.TrimSpace(value)
+    if value == "" {
+        return fmt.Errorf("invalid change %q: missing value", change)
+    }
+
+    switch strings.ToUpper(instruction) {
+    case "CMD":
+        config.Cmd = parseCommandForm(value)
+    case "ENTRYPOINT":
+        config.Entrypoint = parseCommandForm(value)
+    case "ENV":
+        env, err := parseEnvInstruction(value)
+        if err != nil {
+            return fmt.Errorf("invalid change %q: %v", change, err)
+        }
+        config.Env = mergeEnv(config.Env, env)
+    case "WORKDIR":
+        if !filepath.IsAbs(value) {
+            return fmt.Errorf("invalid change %q: working directory must be absolute", change)
+        }
+        config.WorkingDir = value
+    case "USER":
+        config.User = value
+    case "EXPOSE":
+        for _, port := range strings.Fields(value) {
+            if !strings.Contains(port, "/") {
+                port += "/tcp"
+            }
+            if !containsString(config.ExposedPorts, port) {
+                config.ExposedPorts = append(config.ExposedPorts, port)
+            }
+        }
+    case "STOPSIGNAL":
+        if _, err := parseSignal(value); err != nil {
+            return fmt.Errorf("invalid change %q: %v", change, err)
+        }
+        config.StopSignal = value
+    default:
+        return fmt.Errorf("unsupported change instruction %q", instruction)
+    }
+    return nil
+}
+
+// JSON 배열 형식은 그대로, 그 외(셸 형식)는 /bin/sh -c 로 실행
+func parseCommandForm(value string) []string {
+    var args []string
+    if strings.HasPrefix(value, "[") && json.Unmarshal([]byte(value), &args) == nil {
+        return args
+    }
+    return []string{"/bin/sh", "-c", value}
+}
+
+// "KEY=value KEY2=value2" 또는 "KEY value" 형식
+// KEY=value 형식에서는 Dockerfile과 같이 따옴표와 \ 이스케이프로 공백을 값에 넣을 수 있음 (예: A="x y")
+func parseEnvInstruction(value string) ([]string, error) {
+    if !strings.Contains(strings.Fields(value)[0], "=") {
+        key, rest, _ := strings.Cut(value, " ")
+        return []string{key + "=" + strings.TrimSpace(rest)}, nil
+    }
+    words, err := splitQuotedWords(value)
+    if err != nil {
+        return nil, err
+    }
+    var env []string
+    for _, kv := range words {
+        if key, _, ok := strings.Cut(kv, "="); !ok || key == "" {
+            return nil, fmt.Errorf("expected KEY=value, got %q", kv)
+        }
+        env = append(env, kv)
+    }
+    return env, nil
+}
+
+// 공백으로 단어를 나누되 작은따옴표 안은 그대로, 큰따옴표 안과 밖에서는 \ 다음 글자를 그대로 사용
+func splitQuotedWords(value string) ([]string, error) {
+    var words []string
+    var word strings.Builder
+    inWord := false
+    var quote byte
+    for i := 0; i < len(value); i++ {
+        c := value[i]
+        switch {
+        case quote != 0:
+            if c == quote {
+                quote = 0
+                continue
+            }
+            if c == '\\' && quote == '"' && i+1 < len(value) {
+                i++
+                c = value[i]
+            }
+            word.WriteByte(c)
+        case c == '"' || c == '\'':
+            quote = c
+            inWord = true
+        case c == '\\' && i+1 < len(value):
+            i++
+            word.WriteByte(value[i])
+            inWord = true
+        case c == ' ' || c == '\t':
+            if inWord {
+                words = append(words, word.String())
+                word.Reset()
+                inWord = false
+            }
+        default:
+            word.WriteByte(c)
+            inWord = true
+        }
+    }
+    if quote != 0 {
+        return nil, fmt.Errorf("unterminated quote in %q", value)
+    }
+    if inWord {
+        words = append(words, word.String())
+    }
+    return words, nil
+}
+
+// 슬라이스까지 복사한 이미지 설정
+func copyImageConfig(config imageConfig) imageConfig {
+    config.Entrypoint = append([]string(nil), config.Entrypoint...)
+    config.Cmd = append([]string(nil), config.Cmd...)
+    config.Env = append([]string(nil), config.Env...)
+    config.ExposedPorts = append([]string(nil), config.ExposedPorts...)
+    return config
+}
+
+func containsString(list []string, value string) bool {
+    for _, item := range list {
+        if item == value {
+            return true
+        }
+    }
+    return false
+}
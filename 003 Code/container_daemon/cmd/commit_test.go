@@ -0,0 +1,148 @@
+package cmd
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestParseEnvInstruction(t *testing.T) {
+    tests := []struct {
+        value   string
+        want    []string
+        wantErr bool
+    }{
+        {value: "A=1", want: []string{"A=1"}},
+        {value: "A=1 B=2", want: []string{"A=1", "B=2"}},
+        {value: "A=", want: []string{"A="}},
+        {value: "A=x=y", want: []string{"A=x=y"}},
+        {value: `A="x y" B=z`, want: []string{"A=x y", "B=z"}},
+        {value: `A='x "y"'`, want: []string{`A=x "y"`}},
+        {value: `A="say \"hi\""`, want: []string{`A=say "hi"`}},
+        {value: `A=x\ y`, want: []string{"A=x y"}},
+        {value: `A='a\b'`, want: []string{`A=a\b`}},
+        {value: `A="" B=2`, want: []string{"A=", "B=2"}},
+        // 옛 형식: 첫 공백 뒤는 모두 값
+        {value: "GREETING hello  world", want: []string{"GREETING=hello  world"}},
+        {value: "A=1 B", wantErr: true},
+        {value: "A=1 =2", wantErr: true},
+        {value: `A="x y`, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.value, func(t *testing.T) {
+            got, err := parseEnvInstruction(tt.value)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parseEnvInstruction(%q) = %q, want error", tt.value, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("parseEnvInstruction(%q) = %q, want %q", tt.value, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestApplyImageChange(t *testing.T) {
+    base := imageConfig{
+        Cmd:          []string{"/bin/sh"},
+        Env:          []string{"PATH=/bin", "MODE=base"},
+        ExposedPorts: []string{"80/tcp"},
+    }
+
+    tests := []struct {
+        change  string
+        want    imageConfig
+        wantErr bool
+    }{
+        {change: `CMD ["nginx", "-g", "daemon off;"]`, want: imageConfig{Cmd: []string{"nginx", "-g", "daemon off;"}}},
+        {change: "cmd echo hi", want: imageConfig{Cmd: []string{"/bin/sh", "-c", "echo hi"}}},
+        {change: `ENTRYPOINT ["/entry"]`, want: imageConfig{Entrypoint: []string{"/entry"}}},
+        {change: "ENTRYPOINT [broken", want: imageConfig{Entrypoint: []string{"/bin/sh", "-c", "[broken"}}},
+        {change: `ENV MODE="a b" NEW=1`, want: imageConfig{Env: []string{"PATH=/bin", "MODE=a b", "NEW=1"}}},
+        {change: "WORKDIR /srv", want: imageConfig{WorkingDir: "/srv"}},
+        {change: "USER app:app", want: imageConfig{User: "app:app"}},
+        {change: "EXPOSE 80 443 53/udp", want: imageConfig{ExposedPorts: []string{"80/tcp", "443/tcp", "53/udp"}}},
+        {change: "STOPSIGNAL SIGQUIT", want: imageConfig{StopSignal: "SIGQUIT"}},
+        {change: "WORKDIR srv", wantErr: true},
+        {change: "STOPSIGNAL NOPE", wantErr: true},
+        {change: "ENV A=1 B", wantErr: true},
+        {change: "CMD", wantErr: true},
+        {change: "RUN make", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.change, func(t *testing.T) {
+            config := copyImageConfig(base)
+            err := applyImageChange(&config, tt.change)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("applyImageChange(%q) = %+v, want error", tt.change, config)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            // 바꾸지 않은 항목은 원래 값 유지
+            want := copyImageConfig(base)
+            if tt.want.Cmd != nil {
+                want.Cmd = tt.want.Cmd
+            }
+            if tt.want.Entrypoint != nil {
+                want.Entrypoint = tt.want.Entrypoint
+            }
+            if tt.want.Env != nil {
+                want.Env = tt.want.Env
+            }
+            if tt.want.ExposedPorts != nil {
+                want.ExposedPorts = tt.want.ExposedPorts
+            }
+            if tt.want.WorkingDir != "" {
+                want.WorkingDir = tt.want.WorkingDir
+            }
+            if tt.want.User != "" {
+                want.User = tt.want.User
+            }
+            if tt.want.StopSignal != "" {
+                want.StopSignal = tt.want.StopSignal
+            }
+            if !reflect.DeepEqual(config, want) {
+                t.Fatalf("applyImageChange(%q) = %+v, want %+v", tt.change, config, want)
+            }
+        })
+    }
+}
+
+// 복사본에 변경을 적용해도 부모 이미지 설정은 바뀌지 않아야 함
+func TestCopyImageConfigIsolatesParent(t *testing.T) {
+    env := make([]string, 2, 4)
+    copy(env, []string{"PATH=/bin", "MODE=base"})
+    ports := make([]string, 1, 4)
+    ports[0] = "80/tcp"
+    parent := imageConfig{Env: env, ExposedPorts: ports, Cmd: []string{"/bin/sh"}}
+
+    config := copyImageConfig(parent)
+    for _, change := range []string{"ENV MODE=child", "EXPOSE 443", "CMD run"} {
+        if err := applyImageChange(&config, change); err != nil {
+            t.Fatal(err)
+        }
+    }
+    config.Cmd[0] = "changed"
+
+    want := imageConfig{Env: []string{"PATH=/bin", "MODE=base"}, ExposedPorts: []string{"80/tcp"}, Cmd: []string{"/bin/sh"}}
+    if !reflect.DeepEqual(parent, want) {
+        t.Fatalf("parent config = %+v, want %+v", parent, want)
+    }
+    if got := env[:cap(env)][2]; got != "" {
+        t.Fatalf("parent env backing array was written: %q", got)
+    }
+    if got := ports[:cap(ports)][1]; got != "" {
+        t.Fatalf("parent ports backing array was written: %q", got)
+    }
+}
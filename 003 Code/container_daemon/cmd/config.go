@@ -0,0 +1,268 @@
+package cmd
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+
+const defaultPathEnv = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// 이미지에 실행 설정이 없을 때 사용하는 기본 명령
+var defaultContainerCmd = []string{"/bin/busybox", "sh"}
+
+// imageConfig : 이미지에 기록된 실행 설정 (메타데이터 디렉토리의 image.json)
+type imageConfig struct {
+    Entrypoint   []string `json:"entrypoint,omitempty"`
+    Cmd          []string `json:"cmd,omitempty"`
+    Env          []string `json:"env,omitempty"`
+    WorkingDir   string   `json:"workingDir,omitempty"`
+    User         string   `json:"user,omitempty"`
+    ExposedPorts []string `json:"exposedPorts,omitempty"`
+    StopSignal   string   `json:"stopSignal,omitempty"`
+}
+
+// processSpec : 컨테이너 안에서 실행할 프로세스
+type processSpec struct {
+    Args      []string `json:"args"`
+    Env       []string `json:"env"`
+    Cwd       string   `json:"cwd"`
+    User      string   `json:"user,omitempty"` // uid[:gid] 또는 이름
+    Terminal  bool     `json:"terminal"`
+    OpenStdin bool     `json:"openStdin"`
+}
+
+// startOptions : 컨테이너 실행 설정 전체 (메타데이터 디렉토리의 config.json 에 저장되어 restart에서 재사용)
+type startOptions struct {
+    Process      processSpec        `json:"process"`
+    Hostname     string             `json:"hostname"`
+    ExtraHosts   []extraHost        `json:"extraHosts,omitempty"`
+    DNS          []string           `json:"dns,omitempty"`
+    Ports        []portMapping      `json:"ports,omitempty"`
+    Volumes      []volumeMount      `json:"volumes,omitempty"`
+    Resources    containerResources `json:"resources"`
+    ReadOnly     bool               `json:"readOnly"`
+    Init         bool               `json:"init"` // PID 1로 carte init 실행
+    Tmpfs        []tmpfsMount       `json:"tmpfs,omitempty"`
+    Seccomp      string             `json:"seccomp"`      // default, unconfined 또는 프로파일 경로
+    Capabilities []string           `json:"capabilities"` // 컨테이너 프로세스의 최종 권한
+    LogMaxSize   int64              `json:"logMaxSize"`
+    LogMaxFiles  int                `json:"logMaxFiles"`
+}
+
+func containerConfigPath(containerName string) string {
+    return filepath.Join(containerMetaDir(containerName), "config.json")
+}
+
+func containerImageConfigPath(containerName string) string {
+    return filepath.Join(containerMetaDir(containerName), "image.json")
+}
+
+// 컨테이너를 만든 이미지의 실행 설정
+// 생성 때 기록한 image.json이 없으면 상태 파일의 이미지 ID로 이미지 저장소에서 찾아 기록하고,
+// 이미지 없이 만든 컨테이너(스크립트로 구성한 루트 등)는 빈 설정
+func loadContainerImageConfig(containerName string) (*imageConfig, error) {
+    config := &imageConfig{}
+    data, err := os.ReadFile(containerImageConfigPath(containerName))
+    if os.IsNotExist(err) {
+        state, err := readContainerState(containerName)
+        if err != nil || state.Image == "" {
+            return config, nil
+        }
+        img, err := loadImageRecord(state.Image)
+        if err != nil {
+            return nil, err
+        }
+        if err := saveContainerImageConfig(containerName, &img.Config); err != nil {
+            return nil, err
+        }
+        return &img.Config, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to read image config: %v", err)
+    }
+    if err := json.Unmarshal(data, config); err != nil {
+        return nil, fmt.Errorf("failed to parse image config: %v", err)
+    }
+    return config, nil
+}
+
+func saveContainerImageConfig(containerName string, config *imageConfig) error {
+    if err := os.MkdirAll(containerMetaDir(containerName), 0700); err != nil {
+        return fmt.Errorf("failed to create container meta directory: %v", err)
+    }
+    data, err := json.MarshalIndent(config, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(containerImageConfigPath(containerName), data, 0644)
+}
+
+func loadContainerConfig(containerName string) (*startOptions, error) {
+    data, err := os.ReadFile(containerConfigPath(containerName))
+    if err != nil {
+        return nil, fmt.Errorf("failed to read config for container %s: %v", containerName, err)
+    }
+    var opts startOptions
+    if err := json.Unmarshal(data, &opts); err != nil {
+        return nil, fmt.Errorf("failed to parse config for container %s: %v", containerName, err)
+    }
+    return &opts, nil
+}
+
+func saveContainerConfig(containerName string, opts *startOptions) error {
+    if err := os.MkdirAll(containerMetaDir(containerName), 0700); err != nil {
+        return fmt.Errorf("failed to create container meta directory: %v", err)
+    }
+    data, err := json.MarshalIndent(opts, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(containerConfigPath(containerName), data, 0644)
+}
+
+// 이미지 설정과 명령행 인자를 합쳐 실행할 프로세스 결정
+// 명령을 주면 이미지의 CMD를 대체하고 ENTRYPOINT는 유지
+func resolveProcessSpec(image *imageConfig, cmdArgs, env, envFiles []string, workdir, user string) (processSpec, error) {
+    process := processSpec{Cwd: "/"}
+
+    command := image.Cmd
+    if len(cmdArgs) > 0 {
+        command = cmdArgs
+    }
+    process.Args = append(append([]string{}, image.Entrypoint...), command...)
+    if len(process.Args) == 0 {
+        process.Args = defaultContainerCmd
+    }
+
+    // 환경 변수 우선순위: 기본 PATH < 이미지 ENV < --env-file < -e
+    process.Env = mergeEnv([]string{defaultPathEnv}, image.Env)
+    for _, envFile := range envFiles {
+        fileEnv, err := readEnvFile(envFile)
+        if err != nil {
+            return process, err
+        }
+        process.Env = mergeEnv(process.Env, fileEnv)
+    }
+    process.Env = mergeEnv(process.Env, env)
+
+    if image.WorkingDir != "" {
+        process.Cwd = image.WorkingDir
+    }
+    if workdir != "" {
+        process.Cwd = workdir
+    }
+    if !filepath.IsAbs(process.Cwd) {
+        return process, fmt.Errorf("working directory %q must be absolute", process.Cwd)
+    }
+
+    process.User = image.User
+    if user != "" {
+        process.User = user
+    }
+
+    return process, nil
+}
+
+// KEY=VAL 형식의 env 파일 읽기 (빈 줄과 # 주석은 무시)
+func readEnvFile(path string) ([]string, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open env file: %v", err)
+    }
+    defer file.Close()
+
+    var env []string
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        env = append(env, line)
+    }
+    if err := scanner.Err(); err != nil {
+        return nil, fmt.Errorf("failed to read env file %s: %v", path, err)
+    }
+    return env, nil
+}
+
+// 컨테이너의 /etc/passwd, /etc/group 기준으로 user[:group] 해석
+func lookupContainerUser(containerPath, user string) (uint32, uint32, string, error) {
+    if user == "" {
+        return 0, 0, "/root", nil
+    }
+
+    userPart, groupPart, hasGroup := strings.Cut(user, ":")
+
+    uid, gid, home := -1, -1, "/"
+    passwd, _ := readContainerDB(containerPath, "/etc/passwd")
+    for _, fields := range passwd {
+        // name:password:uid:gid:gecos:home:shell
+        if len(fields) < 6 {
+            continue
+        }
+        if fields[0] == userPart || fields[2] == userPart {
+            uid, _ = strconv.Atoi(fields[2])
+            gid, _ = strconv.Atoi(fields[3])
+            home = fields[5]
+            break
+        }
+    }
+    if uid < 0 {
+        n, err := strconv.Atoi(userPart)
+        if err != nil {
+            return 0, 0, "", fmt.Errorf("unable to find user %s in container", userPart)
+        }
+        // passwd에 없는 숫자 UID는 Docker와 같이 그룹 0으로 실행 (다른 그룹은 uid:gid로 지정)
+        uid, gid = n, 0
+    }
+
+    if hasGroup {
+        found := false
+        groups, _ := readContainerDB(containerPath, "/etc/group")
+        for _, fields := range groups {
+            // name:password:gid:members
+            if len(fields) >= 3 && (fields[0] == groupPart || fields[2] == groupPart) {
+                gid, _ = strconv.Atoi(fields[2])
+                found = true
+                break
+            }
+        }
+        if !found {
+            n, err := strconv.Atoi(groupPart)
+            if err != nil {
+                return 0, 0, "", fmt.Errorf("unable to find group %s in container", groupPart)
+            }
+            gid = n
+        }
+    }
+
+    return uint32(uid), uint32(gid), home, nil
+}
+
+// 컨테이너 안의 콜론 구분 파일(/etc/passwd 등)을 필드 단위로 읽기
+func readContainerDB(containerPath, path string) ([][]string, error) {
+    hostPath, err := resolveInRoot(containerPath, path)
+    if err != nil {
+        return nil, err
+    }
+    data, err := os.ReadFile(hostPath)
+    if err != nil {
+        return nil, err
+    }
+
+    var entries [][]string
+    for _, line := range strings.Split(string(data), "\n") {
+        line = strings.TrimSpace(line)
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        entries = append(entries, strings.Split(line, ":"))
+    }
+    return entries, nil
+}
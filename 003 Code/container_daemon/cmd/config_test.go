@@ -0,0 +1,135 @@
+package cmd
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+func TestLookupContainerUser(t *testing.T) {
+    root := t.TempDir()
+    if err := os.MkdirAll(filepath.Join(root, "etc"), 0755); err != nil {
+        t.Fatal(err)
+    }
+    files := map[string]string{
+        "etc/passwd": "# users\nroot:x:0:0:root:/root:/bin/sh\napp:x:1000:1000::/home/app:/bin/sh\n\nnobody:x:65534:65534::/nonexistent:/bin/false\n",
+        "etc/group":  "root:x:0:\nstaff:x:50:app\napp:x:1000:\n",
+    }
+    for name, content := range files {
+        if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    tests := []struct {
+        user    string
+        uid     uint32
+        gid     uint32
+        home    string
+        wantErr bool
+    }{
+        {user: "", uid: 0, gid: 0, home: "/root"},
+        {user: "app", uid: 1000, gid: 1000, home: "/home/app"},
+        {user: "1000", uid: 1000, gid: 1000, home: "/home/app"},
+        {user: "app:staff", uid: 1000, gid: 50, home: "/home/app"},
+        {user: "app:50", uid: 1000, gid: 50, home: "/home/app"},
+        {user: "nobody", uid: 65534, gid: 65534, home: "/nonexistent"},
+        // passwd에 없는 숫자 UID는 그룹 0
+        {user: "1234", uid: 1234, gid: 0, home: "/"},
+        {user: "1234:1234", uid: 1234, gid: 1234, home: "/"},
+        {user: "1234:staff", uid: 1234, gid: 50, home: "/"},
+        {user: "ghost", wantErr: true},
+        {user: "app:ghosts", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.user, func(t *testing.T) {
+            uid, gid, home, err := lookupContainerUser(root, tt.user)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("lookupContainerUser(%q) = %d:%d, want error", tt.user, uid, gid)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if uid != tt.uid || gid != tt.gid || home != tt.home {
+                t.Fatalf("lookupContainerUser(%q) = %d:%d %s, want %d:%d %s", tt.user, uid, gid, home, tt.uid, tt.gid, tt.home)
+            }
+        })
+    }
+}
+
+func TestResolveProcessSpec(t *testing.T) {
+    envFile := filepath.Join(t.TempDir(), "env")
+    if err := os.WriteFile(envFile, []byte("# comment\nMODE=file\n\nFROM_FILE=1\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    image := &imageConfig{
+        Entrypoint: []string{"/entry"},
+        Cmd:        []string{"serve"},
+        Env:        []string{"MODE=image", "LANG=C"},
+        WorkingDir: "/app",
+        User:       "app",
+    }
+
+    tests := []struct {
+        name     string
+        image    *imageConfig
+        cmdArgs  []string
+        env      []string
+        envFiles []string
+        workdir  string
+        user     string
+        want     processSpec
+        wantErr  bool
+    }{
+        {
+            name:  "empty image",
+            image: &imageConfig{},
+            want:  processSpec{Args: defaultContainerCmd, Env: []string{defaultPathEnv}, Cwd: "/"},
+        },
+        {
+            name:  "image config",
+            image: image,
+            want:  processSpec{Args: []string{"/entry", "serve"}, Env: []string{defaultPathEnv, "MODE=image", "LANG=C"}, Cwd: "/app", User: "app"},
+        },
+        {
+            name:     "overrides",
+            image:    image,
+            cmdArgs:  []string{"check", "--all"},
+            env:      []string{"MODE=flag", "PATH=/bin"},
+            envFiles: []string{envFile},
+            workdir:  "/srv",
+            user:     "0:0",
+            want: processSpec{
+                Args: []string{"/entry", "check", "--all"},
+                Env:  []string{"PATH=/bin", "MODE=flag", "LANG=C", "FROM_FILE=1"},
+                Cwd:  "/srv",
+                User: "0:0",
+            },
+        },
+        {name: "relative workdir", image: image, workdir: "srv", wantErr: true},
+        {name: "missing env file", image: image, envFiles: []string{envFile + ".missing"}, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := resolveProcessSpec(tt.image, tt.cmdArgs, tt.env, tt.envFiles, tt.workdir, tt.user)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("resolveProcessSpec() = %+v, want error", got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("resolveProcessSpec() = %+v, want %+v", got, tt.want)
+            }
+        })
+    }
+}
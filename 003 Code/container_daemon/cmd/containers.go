@@ -1,27 +1,36 @@
 package cmd
 
 import (
+    "encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
+    "strconv"
     "strings"
+    "text/tabwriter"
 
 	"github.com/spf13/cobra"
 )
 
+var listFormat string
+
 var psListCmd = &cobra.Command{
-	Use:   "list_c",
-	Short: "List all containers with their running status",
+	Use:     "list_c",
+	Aliases: []string{"ps"},
+	Short:   "List all containers with their status",
 	RunE: func(cmd *cobra.Command, args []string) error {
+        if listFormat != "table" && listFormat != "json" {
+            return fmt.Errorf("unknown format %q, expected table or json", listFormat)
+        }
 		return listRunningContainers("/CarteDaemon/container")
 	},
 }
 
 func init() {
+    psListCmd.Flags().StringVar(&listFormat, "format", "table", "Output format (table or json)")
 	rootCmd.AddCommand(psListCmd)
 }
 
-// 컨테이너 목록을 확인하고 실행 여부를 표시하는 함수
+// 컨테이너 목록과 상태를 표시하는 함수
 func listRunningContainers(containerDir string) error {
     // 컨테이너 디렉토리에서 컨테이너 목록 가져오기
     files, err := os.ReadDir(containerDir)
@@ -29,66 +38,74 @@ func listRunningContainers(containerDir string) error {
         return fmt.Errorf("failed to read container directory: %v", err)
     }
 
-    // fmt.Println("---- Running Containers ----")
-    fmt.Println("  Container List [running]")
-    fmt.Println("===========================")
-
+    var states []*containerState
     for _, file := range files {
-        if file.IsDir() {
-            containerName := file.Name()
-            isRunning, err := isContainerRunning(containerName)
-            if err != nil {
-                fmt.Printf("%s   Error: %v\n", containerName, err)
-                continue
-            }
-
-            // 실행 중이면 T, 그렇지 않으면 F로 표시
-            runningStatus := "F"
-            if isRunning {
-                runningStatus = "T"
-            }
+        if !file.IsDir() {
+            continue
+        }
+        state, err := loadContainerState(file.Name())
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "%s: %v\n", file.Name(), err)
+            continue
+        }
+        states = append(states, state)
+    }
 
-            fmt.Printf("[%s] %s\n", containerName, runningStatus)
+    if listFormat == "json" {
+        data, err := json.MarshalIndent(states, "", "  ")
+        if err != nil {
+            return err
         }
+        fmt.Println(string(data))
+        return nil
     }
 
-    return nil
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+    fmt.Fprintln(w, "CONTAINER ID\tNAME\tSTATUS\tPID\tIP\tPORTS")
+    for _, state := range states {
+        pid, ip, ports := "-", "-", ""
+        // 실행 중인 컨테이너는 PID, IP와 공개 포트도 함께 표시
+        if state.isActive() {
+            pid = strconv.Itoa(state.Pid)
+            if network, err := loadContainerNetwork(state.Name); err == nil {
+                ip = network.ContainerIP
+                var published []string
+                for _, port := range network.Ports {
+                    published = append(published, port.String())
+                }
+                ports = strings.Join(published, ", ")
+            }
+        }
+        fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", shortID(state.ID), state.Name, state.describe(), pid, ip, ports)
+    }
+    return w.Flush()
 }
 
+// 목록에 표시하는 짧은 ID
+func shortID(id string) string {
+    if len(id) > 12 {
+        return id[:12]
+    }
+    return id
+}
 
+// 컨테이너 프로세스가 존재하는지 확인 (일시 정지 상태 포함)
 func isContainerRunning(containerName string) (bool, error) {
-    // PID 파일 경로 설정
-    pidFilePath := "/CarteDaemon/container/" + containerName + "/pid"
-    // fmt.Printf("Checking container %s at %s\n", containerName, pidFilePath)
-
-    // PID 파일이 있는지 확인
-    if _, err := os.Stat(pidFilePath); os.IsNotExist(err) {
-        // PID 파일이 존재하지 않으면 실행되지 않음으로 간주
-        fmt.Printf("PID file does not exist for container %s\n", containerName)
-        return false, nil
-    } else if err != nil {
-        // 다른 오류 발생 시 에러 반환
-        return false, fmt.Errorf("error checking PID file: %v", err)
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return false, err
     }
+    return state.isActive(), nil
+}
 
-    // PID 파일에서 PID 읽기
-    pidData, err := os.ReadFile(pidFilePath)
+// 실행 중인 컨테이너 프로세스의 PID
+func readContainerPID(containerName string) (int, error) {
+    state, err := loadContainerState(containerName)
     if err != nil {
-        return false, fmt.Errorf("error reading PID file: %v", err)
+        return 0, err
     }
-
-    // PID를 문자열에서 정수로 변환
-    pid := strings.TrimSpace(string(pidData))
-    // fmt.Printf("Found PID for container %s: %s\n", containerName, pid)
-
-    // ps 명령을 통해 해당 PID가 실행 중인지 확인
-    cmd := exec.Command("ps", "-p", pid)
-    //output, err := cmd.CombinedOutput()
-    if err := cmd.Run(); err != nil {
-        // fmt.Printf("ps command failed for PID %s: %v\nOutput: %s\n", pid, err, string(output))
-        return false, nil
+    if !state.isActive() {
+        return 0, fmt.Errorf("container %s is not running", containerName)
     }
-
-    // fmt.Printf("ps command succeeded for PID %s: Output: %s\n", pid, string(output))
-    return true, nil
+    return state.Pid, nil
 }
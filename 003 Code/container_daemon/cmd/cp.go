@@ -0,0 +1,405 @@
+package cmd
+
+import (
+    "archive/tar"
+    "fmt"
+    "io"
+    "io/fs"
+    "os"
+    "path"
+    "path/filepath"
+    "strings"
+    "syscall"
+
+    "github.com/spf13/cobra"
+    "golang.org/x/sys/unix"
+)
+
+var cpFollowLink bool
+
+var cpCmd = &cobra.Command{
+    Use:   "cp [container:]srcPath|- [container:]destPath|-",
+    Short: "Copy files between a container and the host (- streams a tar archive on stdin/stdout)",
+    Args:  cobra.ExactArgs(2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        srcContainer, srcPath := splitCopyArg(args[0])
+        dstContainer, dstPath := splitCopyArg(args[1])
+
+        switch {
+        case srcContainer != "" && dstContainer != "":
+            return fmt.Errorf("copying between containers is not supported")
+        case srcContainer == "" && dstContainer == "":
+            return fmt.Errorf("one of source or destination must be a container path (container:path)")
+        case srcContainer != "":
+            if srcPath == "-" {
+                return fmt.Errorf("invalid source path %q", args[0])
+            }
+            containerName, err := resolveContainer(srcContainer)
+            if err != nil {
+                return err
+            }
+            return copyFromContainer(containerName, srcPath, dstPath)
+        }
+        if dstPath == "-" {
+            return fmt.Errorf("invalid destination path %q", args[1])
+        }
+        containerName, err := resolveContainer(dstContainer)
+        if err != nil {
+            return err
+        }
+        return copyToContainer(containerName, srcPath, dstPath)
+    },
+}
+
+func init() {
+    cpCmd.Flags().BoolVarP(&cpFollowLink, "follow-link", "L", false, "Follow a symbolic link given as the source path")
+    rootCmd.AddCommand(cpCmd)
+}
+
+// "container:path" 분리 (/ 나 . 으로 시작하면 ':'가 있어도 호스트 경로)
+func splitCopyArg(arg string) (string, string) {
+    if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, ".") {
+        return "", arg
+    }
+    container, p, found := strings.Cut(arg, ":")
+    if !found || container == "" || strings.Contains(container, "/") {
+        return "", arg
+    }
+    return container, p
+}
+
+// copyEndpoint : 복사의 한쪽 (root 밖으로 나가는 경로는 root 기준으로 해석됨, 호스트는 root가 "/")
+type copyEndpoint struct {
+    root      string
+    describe  string
+    whiteouts bool // 이미지 레이어를 풀 때 whiteout 항목을 삭제로 적용
+}
+
+func containerEndpoint(containerName string) copyEndpoint {
+    return copyEndpoint{
+        root:     filepath.Join("/CarteDaemon/container", containerName),
+        describe: "container " + containerName,
+    }
+}
+
+var hostEndpoint = copyEndpoint{root: "/", describe: "host"}
+
+// 복사 원본 해석: 마지막 경로 요소의 심볼릭 링크는 -L이나 끝의 '/'가 없으면 링크 자체를 복사
+func (e copyEndpoint) resolveSource(p string) (string, error) {
+    clean := filepath.Clean("/" + p)
+    var hostPath string
+    var err error
+    if cpFollowLink || strings.HasSuffix(p, "/") || strings.HasSuffix(p, "/.") || clean == "/" {
+        hostPath, err = resolveInRoot(e.root, clean)
+    } else {
+        var parent string
+        parent, err = resolveInRoot(e.root, filepath.Dir(clean))
+        hostPath = filepath.Join(parent, filepath.Base(clean))
+    }
+    if err != nil {
+        return "", fmt.Errorf("failed to resolve %s in %s: %v", p, e.describe, err)
+    }
+    if _, err := os.Lstat(hostPath); err != nil {
+        return "", fmt.Errorf("could not find %s in %s", p, e.describe)
+    }
+    return hostPath, nil
+}
+
+func copyFromContainer(containerName, srcPath, dstPath string) error {
+    src := containerEndpoint(containerName)
+    srcHost, err := src.resolveSource(srcPath)
+    if err != nil {
+        return err
+    }
+    if dstPath == "-" {
+        return writeArchive(os.Stdout, srcHost, copyName(srcPath), copyContentsOnly(srcPath))
+    }
+    dstAbs, err := filepath.Abs(dstPath)
+    if err != nil {
+        return err
+    }
+    return copyPath(srcHost, srcPath, hostEndpoint, dstAbs, dstPath)
+}
+
+func copyToContainer(containerName, srcPath, dstPath string) error {
+    dst := containerEndpoint(containerName)
+    if srcPath == "-" {
+        // tar 스트림은 기존 디렉토리 안에 풀기만 함
+        dstHost, err := resolveInRoot(dst.root, filepath.Clean("/"+dstPath))
+        if err != nil {
+            return fmt.Errorf("failed to resolve %s in %s: %v", dstPath, dst.describe, err)
+        }
+        if info, err := os.Stat(dstHost); err != nil || !info.IsDir() {
+            return fmt.Errorf("destination %s must be an existing directory in %s", dstPath, dst.describe)
+        }
+        return extractArchive(os.Stdin, dst, filepath.Clean("/"+dstPath), "", "")
+    }
+
+    srcAbs, err := filepath.Abs(srcPath)
+    if err != nil {
+        return err
+    }
+    // Abs가 지우는 끝의 "/."와 "/"는 복사 방식을 정하므로 다시 붙임
+    if strings.HasSuffix(srcPath, "/.") {
+        srcAbs += "/."
+    } else if strings.HasSuffix(srcPath, "/") {
+        srcAbs += "/"
+    }
+    srcHost, err := hostEndpoint.resolveSource(srcAbs)
+    if err != nil {
+        return err
+    }
+    return copyPath(srcHost, srcPath, dst, filepath.Clean("/"+dstPath), dstPath)
+}
+
+// 복사 항목의 이름 (원본 경로의 마지막 요소)
+func copyName(p string) string {
+    name := filepath.Base(filepath.Clean("/" + p))
+    if name == "/" {
+        return "."
+    }
+    return name
+}
+
+// "dir/." 이나 "/"는 디렉토리 자체가 아니라 내용을 복사
+func copyContentsOnly(p string) bool {
+    return strings.HasSuffix(p, "/.") || filepath.Clean("/"+p) == "/"
+}
+
+// cp와 같은 규칙으로 복사
+// 대상이 디렉토리면 그 안에 같은 이름으로, 없으면 대상 이름으로 바꿔 복사
+func copyPath(srcHost, srcPath string, dst copyEndpoint, dstClean, dstPath string) error {
+    srcInfo, err := os.Lstat(srcHost)
+    if err != nil {
+        return err
+    }
+    contentsOnly := copyContentsOnly(srcPath) && srcInfo.IsDir()
+    name := copyName(srcPath)
+
+    dstHost, err := resolveInRoot(dst.root, dstClean)
+    if err != nil {
+        return fmt.Errorf("failed to resolve %s in %s: %v", dstPath, dst.describe, err)
+    }
+
+    extractDir, rebaseTo := dstClean, ""
+    dstInfo, err := os.Stat(dstHost)
+    switch {
+    case err == nil && dstInfo.IsDir():
+        // 디렉토리 안으로 복사
+    case err == nil:
+        if srcInfo.IsDir() {
+            return fmt.Errorf("cannot copy a directory to file %s", dstPath)
+        }
+        extractDir, rebaseTo = filepath.Dir(dstClean), filepath.Base(dstClean)
+    case os.IsNotExist(err):
+        if strings.HasSuffix(dstPath, "/") && !srcInfo.IsDir() {
+            return fmt.Errorf("destination directory %s does not exist", dstPath)
+        }
+        parent, err := os.Stat(filepath.Dir(dstHost))
+        if err != nil || !parent.IsDir() {
+            return fmt.Errorf("destination directory %s does not exist", filepath.Dir(dstPath))
+        }
+        if contentsOnly {
+            if err := os.Mkdir(dstHost, srcInfo.Mode().Perm()); err != nil {
+                return fmt.Errorf("failed to create %s: %v", dstPath, err)
+            }
+        } else {
+            extractDir, rebaseTo = filepath.Dir(dstClean), filepath.Base(dstClean)
+        }
+    default:
+        return fmt.Errorf("failed to stat %s: %v", dstPath, err)
+    }
+
+    reader, writer := io.Pipe()
+    go func() {
+        writer.CloseWithError(writeArchive(writer, srcHost, name, contentsOnly))
+    }()
+    err = extractArchive(reader, dst, extractDir, name, rebaseTo)
+    reader.CloseWithError(err)
+    return err
+}
+
+// srcHost를 name이라는 이름으로 tar에 기록 (contentsOnly면 디렉토리 내용만 최상위에)
+func writeArchive(w io.Writer, srcHost, name string, contentsOnly bool) error {
+    tw := tar.NewWriter(w)
+    err := filepath.WalkDir(srcHost, func(fullPath string, entry fs.DirEntry, err error) error {
+        if err != nil {
+            return err
+        }
+        rel, err := filepath.Rel(srcHost, fullPath)
+        if err != nil {
+            return err
+        }
+        if contentsOnly {
+            if rel == "." {
+                return nil
+            }
+            return writeTarFile(tw, fullPath, filepath.ToSlash(rel))
+        }
+        return writeTarFile(tw, fullPath, path.Join(name, filepath.ToSlash(rel)))
+    })
+    if err != nil {
+        return err
+    }
+    return tw.Close()
+}
+
+// tar를 dst의 dir 아래에 풀기, rebaseFrom으로 시작하는 항목은 rebaseTo로 이름을 바꿈
+// 각 항목은 상위 경로까지만 링크를 따라가 해석하므로 tar 안의 ".."나 링크로 루트를 벗어날 수 없음
+func extractArchive(r io.Reader, dst copyEndpoint, dir, rebaseFrom, rebaseTo string) error {
+    type dirTimes struct {
+        path  string
+        mtime unix.Timespec
+    }
+    var dirs []dirTimes
+
+    reader := tar.NewReader(r)
+    for {
+        header, err := reader.Next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return fmt.Errorf("failed to read archive: %v", err)
+        }
+
+        name := path.Clean("/" + header.Name)
+        if rebaseTo != "" {
+            if name == "/"+rebaseFrom {
+                name = "/" + rebaseTo
+            } else if strings.HasPrefix(name, "/"+rebaseFrom+"/") {
+                name = "/" + rebaseTo + strings.TrimPrefix(name, "/"+rebaseFrom)
+            }
+        }
+        if name == "/" {
+            continue
+        }
+        name = path.Join(filepath.ToSlash(dir), name)
+
+        parent, err := resolveInRoot(dst.root, path.Dir(name))
+        if err != nil {
+            return fmt.Errorf("failed to resolve %s: %v", name, err)
+        }
+        target := filepath.Join(parent, path.Base(name))
+        if dst.whiteouts && strings.HasPrefix(path.Base(name), whiteoutPrefix) {
+            if err := applyWhiteout(parent, path.Base(name)); err != nil {
+                return fmt.Errorf("failed to apply whiteout %s: %v", name, err)
+            }
+            continue
+        }
+        if err := os.MkdirAll(parent, 0755); err != nil {
+            return fmt.Errorf("failed to create %s: %v", path.Dir(name), err)
+        }
+        if err := extractEntry(reader, header, dst, target); err != nil {
+            return fmt.Errorf("failed to extract %s: %v", name, err)
+        }
+
+        mtime := unix.NsecToTimespec(header.ModTime.UnixNano())
+        if header.Typeflag == tar.TypeDir {
+            // 디렉토리 수정 시각은 내용을 모두 푼 뒤에 설정
+            dirs = append(dirs, dirTimes{target, mtime})
+            continue
+        }
+        unix.UtimesNanoAt(unix.AT_FDCWD, target, []unix.Timespec{mtime, mtime}, unix.AT_SYMLINK_NOFOLLOW)
+    }
+
+    for i := len(dirs) - 1; i >= 0; i-- {
+        unix.UtimesNanoAt(unix.AT_FDCWD, dirs[i].path, []unix.Timespec{dirs[i].mtime, dirs[i].mtime}, unix.AT_SYMLINK_NOFOLLOW)
+    }
+    return nil
+}
+
+// .wh.<name>은 아래 레이어의 name을, .wh..wh..opq는 디렉토리의 기존 내용 전체를 삭제
+func applyWhiteout(parent, base string) error {
+    var targets []string
+    if base == whiteoutOpaque {
+        entries, err := os.ReadDir(parent)
+        if err != nil {
+            return err
+        }
+        for _, entry := range entries {
+            targets = append(targets, filepath.Join(parent, entry.Name()))
+        }
+    } else {
+        name := strings.TrimPrefix(base, whiteoutPrefix)
+        if name == "" || name == "." || name == ".." {
+            return fmt.Errorf("invalid whiteout %q", base)
+        }
+        targets = append(targets, filepath.Join(parent, name))
+    }
+
+    for _, target := range targets {
+        if err := os.RemoveAll(target); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func extractEntry(reader io.Reader, header *tar.Header, dst copyEndpoint, target string) error {
+    mode := uint32(header.Mode & 07777)
+
+    // 디렉토리가 아닌 기존 파일은 지우고 새로 만듦 (기존 링크를 따라 쓰지 않도록)
+    if info, err := os.Lstat(target); err == nil && !(info.IsDir() && header.Typeflag == tar.TypeDir) {
+        if err := os.RemoveAll(target); err != nil {
+            return err
+        }
+    }
+
+    switch header.Typeflag {
+    case tar.TypeDir:
+        if err := os.Mkdir(target, os.FileMode(mode&0777)); err != nil && !os.IsExist(err) {
+            return err
+        }
+    case tar.TypeReg, tar.TypeRegA:
+        file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY|syscall.O_NOFOLLOW, os.FileMode(mode&0777))
+        if err != nil {
+            return err
+        }
+        _, err = io.Copy(file, reader)
+        if closeErr := file.Close(); err == nil {
+            err = closeErr
+        }
+        if err != nil {
+            return err
+        }
+    case tar.TypeSymlink:
+        if err := os.Symlink(header.Linkname, target); err != nil {
+            return err
+        }
+        return chownEntry(target, header)
+    case tar.TypeLink:
+        source, err := resolveInRoot(dst.root, path.Join("/", header.Linkname))
+        if err != nil {
+            return err
+        }
+        return os.Link(source, target)
+    case tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+        fileType := uint32(unix.S_IFIFO)
+        if header.Typeflag == tar.TypeChar {
+            fileType = unix.S_IFCHR
+        } else if header.Typeflag == tar.TypeBlock {
+            fileType = unix.S_IFBLK
+        }
+        dev := unix.Mkdev(uint32(header.Devmajor), uint32(header.Devminor))
+        if err := unix.Mknod(target, fileType|mode, int(dev)); err != nil {
+            return err
+        }
+    default:
+        return nil // 지원하지 않는 항목은 건너뜀
+    }
+
+    if err := chownEntry(target, header); err != nil {
+        return err
+    }
+    // chown이 setuid/setgid 비트를 지우므로 권한은 마지막에 설정
+    return unix.Fchmodat(unix.AT_FDCWD, target, mode, 0)
+}
+
+// 파일 소유자 복원 (root가 아니면 복원할 수 없으므로 생략)
+func chownEntry(target string, header *tar.Header) error {
+    if os.Geteuid() != 0 {
+        return nil
+    }
+    return os.Lchown(target, header.Uid, header.Gid)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+    "archive/tar"
+    "bytes"
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestSplitCopyArg(t *testing.T) {
+    tests := []struct {
+        arg           string
+        wantContainer string
+        wantPath      string
+    }{
+        {arg: "web:/etc/hosts", wantContainer: "web", wantPath: "/etc/hosts"},
+        {arg: "web:relative", wantContainer: "web", wantPath: "relative"},
+        {arg: "web:", wantContainer: "web", wantPath: ""},
+        {arg: "/tmp/a:b", wantPath: "/tmp/a:b"},
+        {arg: "./web:/etc", wantPath: "./web:/etc"},
+        {arg: "dir/web:/etc", wantPath: "dir/web:/etc"},
+        {arg: ":/etc", wantPath: ":/etc"},
+        {arg: "file.txt", wantPath: "file.txt"},
+        {arg: "-", wantPath: "-"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.arg, func(t *testing.T) {
+            container, p := splitCopyArg(tt.arg)
+            if container != tt.wantContainer || p != tt.wantPath {
+                t.Fatalf("splitCopyArg(%q) = %q, %q, want %q, %q", tt.arg, container, p, tt.wantContainer, tt.wantPath)
+            }
+        })
+    }
+}
+
+func TestCopyName(t *testing.T) {
+    tests := []struct {
+        path         string
+        want         string
+        contentsOnly bool
+    }{
+        {path: "/etc/hosts", want: "hosts"},
+        {path: "etc/", want: "etc"},
+        {path: "/etc/.", want: "etc", contentsOnly: true},
+        {path: "/", want: ".", contentsOnly: true},
+        {path: "", want: ".", contentsOnly: true},
+        {path: "../../x", want: "x"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.path, func(t *testing.T) {
+            if got := copyName(tt.path); got != tt.want {
+                t.Fatalf("copyName(%q) = %q, want %q", tt.path, got, tt.want)
+            }
+            if got := copyContentsOnly(tt.path); got != tt.contentsOnly {
+                t.Fatalf("copyContentsOnly(%q) = %v, want %v", tt.path, got, tt.contentsOnly)
+            }
+        })
+    }
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+    src := t.TempDir()
+    if err := os.MkdirAll(filepath.Join(src, "data", "sub"), 0755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(filepath.Join(src, "data", "sub", "file"), []byte("hello"), 0640); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Symlink("sub/file", filepath.Join(src, "data", "link")); err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name         string
+        contentsOnly bool
+        rebaseTo     string
+        want         string // 풀린 뒤 파일의 경로
+    }{
+        {name: "directory", want: "data/sub/file"},
+        {name: "contents only", contentsOnly: true, want: "sub/file"},
+        {name: "renamed", rebaseTo: "copy", want: "copy/sub/file"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var buf bytes.Buffer
+            if err := writeArchive(&buf, filepath.Join(src, "data"), "data", tt.contentsOnly); err != nil {
+                t.Fatal(err)
+            }
+            dst := t.TempDir()
+            if err := extractArchive(&buf, copyEndpoint{root: dst}, "/", "data", tt.rebaseTo); err != nil {
+                t.Fatal(err)
+            }
+
+            file := filepath.Join(dst, tt.want)
+            data, err := os.ReadFile(file)
+            if err != nil || string(data) != "hello" {
+                t.Fatalf("read %q, %v from %s, want \"hello\"", data, err, tt.want)
+            }
+            if info, _ := os.Stat(file); info.Mode().Perm() != 0640 {
+                t.Fatalf("mode of %s = %v, want 0640", tt.want, info.Mode().Perm())
+            }
+            link, err := os.Readlink(filepath.Join(filepath.Dir(filepath.Dir(file)), "link"))
+            if err != nil || link != "sub/file" {
+                t.Fatalf("link = %q, %v, want \"sub/file\"", link, err)
+            }
+        })
+    }
+}
+
+// tar 안의 ".."나 앞서 푼 링크를 거쳐도 대상 루트 밖에 쓰지 않아야 함
+func TestExtractArchiveStaysInRoot(t *testing.T) {
+    root := t.TempDir()
+    outside := t.TempDir()
+
+    var buf bytes.Buffer
+    tw := tar.NewWriter(&buf)
+    entries := []tar.Header{
+        {Name: "../../escape", Typeflag: tar.TypeReg, Mode: 0644, Size: 1},
+        {Name: "out", Typeflag: tar.TypeSymlink, Linkname: outside},
+        {Name: "out/file", Typeflag: tar.TypeReg, Mode: 0644, Size: 1},
+    }
+    for i := range entries {
+        if err := tw.WriteHeader(&entries[i]); err != nil {
+            t.Fatal(err)
+        }
+        if entries[i].Size > 0 {
+            tw.Write([]byte("x"))
+        }
+    }
+    tw.Close()
+
+    if err := extractArchive(&buf, copyEndpoint{root: root}, "/", "", ""); err != nil {
+        t.Fatal(err)
+    }
+    if list, _ := os.ReadDir(outside); len(list) != 0 {
+        t.Fatalf("extractArchive() wrote %v outside the root", list)
+    }
+    for _, p := range []string{"escape", filepath.Join(outside, "file")} {
+        if _, err := os.Stat(filepath.Join(root, p)); err != nil {
+            t.Fatalf("%s was not extracted inside the root: %v", p, err)
+        }
+    }
+}
@@ -4,14 +4,16 @@ import (
     "fmt"
     "os"
     "os/exec"
+    "path/filepath"
     "github.com/spf13/cobra"
 )
 
 // image_create 명령 정의
 var imageCreateCmd = &cobra.Command{
-    Use:   "create [scriptFile]",
-    Short: "Create an image using the specified script file",
-    Args:  cobra.ExactArgs(1),
+    Use:        "create [scriptFile]",
+    Short:      "Create an image using the specified script file",
+    Deprecated: "use import to create an image from a root filesystem tarball",
+    Args:       cobra.ExactArgs(1),
     RunE: func(cmd *cobra.Command, args []string) error {
         scriptFile := args[0]
 
@@ -19,14 +21,90 @@ var imageCreateCmd = &cobra.Command{
         if err := executeScript(scriptFile); err != nil {
             return fmt.Errorf("failed to execute script: %v", err)
         }
+        recordEvent(lifecycleEvent{Type: "image", Action: "build", ID: scriptFile, Attributes: map[string]string{"script": scriptFile}})
 
         fmt.Printf("Image created successfully using script %s\n", scriptFile)
         return nil
     },
 }
 
+var createContainerName string
+
+// 저장소 이미지로 컨테이너 생성
+var containerCreateCmd = &cobra.Command{
+    Use:   "create_c [image]",
+    Short: "Create a container from an image in the image store",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        img, err := lookupImage(args[0])
+        if err != nil {
+            return err
+        }
+        return createContainerFromImage(img, createContainerName)
+    },
+}
+
 func init() {
     rootCmd.AddCommand(imageCreateCmd)
+    containerCreateCmd.Flags().StringVar(&createContainerName, "name", "", "Assign a name to the container")
+    rootCmd.AddCommand(containerCreateCmd)
+}
+
+// 컨테이너 ID로 디렉토리를 만들고 이미지 레이어를 아래부터 차례로 풀어 루트 파일시스템 구성
+func createContainerFromImage(img *imageRecord, name string) error {
+    id, err := newContainerID()
+    if err != nil {
+        return err
+    }
+    containerPath := filepath.Join("/CarteDaemon/container", id)
+    if err := os.Mkdir(containerPath, 0755); err != nil {
+        return fmt.Errorf("failed to create container directory: %v", err)
+    }
+
+    if err := populateContainer(id, img, name); err != nil {
+        os.RemoveAll(containerPath)
+        os.RemoveAll(containerMetaDir(id))
+        return err
+    }
+
+    recordContainerEvent(id, "create", nil)
+
+    state, err := loadContainerState(id)
+    if err != nil {
+        return err
+    }
+    fmt.Printf("Created container %s (%s) from image %s\n", state.Name, shortID(state.ID), shortID(img.ID))
+    fmt.Println(state.ID)
+    return nil
+}
+
+func populateContainer(containerName string, img *imageRecord, name string) error {
+    if err := updateContainerState(containerName, func(s *containerState) error {
+        s.Image = img.ID
+        return nil
+    }); err != nil {
+        return err
+    }
+    if name != "" {
+        if err := renameContainer(containerName, name); err != nil {
+            return err
+        }
+    }
+
+    dst := containerEndpoint(containerName)
+    dst.whiteouts = true
+    for _, layer := range img.Layers {
+        file, err := os.Open(layerPath(layer.Digest))
+        if err != nil {
+            return fmt.Errorf("failed to open layer %s: %v", layer.Digest, err)
+        }
+        err = extractArchive(file, dst, "/", "", "")
+        file.Close()
+        if err != nil {
+            return fmt.Errorf("failed to extract layer %s: %v", layer.Digest, err)
+        }
+    }
+    return saveContainerImageConfig(containerName, &img.Config)
 }
 
 // 스크립트 실행 함수
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+    "encoding/json"
+    "fmt"
+    "path/filepath"
+
+    "github.com/spf13/cobra"
+)
+
+var diffFormat string
+
+var diffCmd = &cobra.Command{
+    Use:   "diff [container]",
+    Short: "List added (A), changed (C) and deleted (D) files in a container compared with its image",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if diffFormat != "table" && diffFormat != "json" {
+            return fmt.Errorf("unknown format %q, expected table or json", diffFormat)
+        }
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        return diffContainer(containerName)
+    },
+}
+
+func init() {
+    diffCmd.Flags().StringVar(&diffFormat, "format", "table", "Output format (table or json)")
+    rootCmd.AddCommand(diffCmd)
+}
+
+// 컨테이너 루트는 이미지를 복사한 일반 디렉토리이므로 (overlay의 upperdir가 없음)
+// 이미지 레이어에서 만든 파일 목록과 현재 파일 속성을 비교
+func diffContainer(containerName string) error {
+    base, _, err := containerBaseManifest(containerName)
+    if err != nil {
+        return err
+    }
+    changes, err := computeChanges(filepath.Join("/CarteDaemon/container", containerName), base)
+    if err != nil {
+        return err
+    }
+
+    if diffFormat == "json" {
+        if changes == nil {
+            changes = []fileChange{}
+        }
+        data, err := json.MarshalIndent(changes, "", "  ")
+        if err != nil {
+            return err
+        }
+        fmt.Println(string(data))
+        return nil
+    }
+    for _, change := range changes {
+        fmt.Printf("%s %s\n", change.Kind, change.Path)
+    }
+    return nil
+}
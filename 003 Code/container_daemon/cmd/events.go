@@ -0,0 +1,330 @@
+package cmd
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/spf13/cobra"
+    "golang.org/x/sys/unix"
+)
+
+const (
+    eventsDir        = "/CarteDaemon/events"
+    eventsMaxSize    = 1024 * 1024 // 저널 파일 하나의 최대 크기, 넘으면 events.log.1로 밀어냄 (두 파일만 보관)
+    eventsPollPeriod = 250 * time.Millisecond
+)
+
+// lifecycleEvent : 저널에 한 줄씩 기록되는 이벤트
+// network 이벤트의 id/name은 연결된 컨테이너
+type lifecycleEvent struct {
+    Time       time.Time         `json:"time"`
+    Type       string            `json:"type"`   // container, image, network
+    Action     string            `json:"action"` // create, start, die, oom, kill, stop, pause, unpause, remove, commit, import, build, connect
+    ID         string            `json:"id"`
+    Name       string            `json:"name,omitempty"`
+    Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+var (
+    eventsFilters []string
+    eventsSince   string
+    eventsUntil   string
+    eventsFormat  string
+)
+
+var eventsCmd = &cobra.Command{
+    Use:   "events",
+    Short: "Stream container, image and network lifecycle events",
+    Args:  cobra.NoArgs,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if eventsFormat != "table" && eventsFormat != "json" {
+            return fmt.Errorf("unknown format %q, expected table or json", eventsFormat)
+        }
+        filter, err := parseEventFilters(eventsFilters)
+        if err != nil {
+            return err
+        }
+        var since, until time.Time
+        if eventsSince != "" {
+            if since, err = parseSince(eventsSince); err != nil {
+                return err
+            }
+        }
+        if eventsUntil != "" {
+            if until, err = parseSince(eventsUntil); err != nil {
+                return fmt.Errorf("invalid --until value %q", eventsUntil)
+            }
+        }
+        return showEvents(filter, since, until)
+    },
+}
+
+func init() {
+    eventsCmd.Flags().StringArrayVarP(&eventsFilters, "filter", "f", nil, "Filter events (type=, name=, id=, event=, comma separated or repeated), repeat a key to match any of its values")
+    eventsCmd.Flags().StringVar(&eventsSince, "since", "", "Replay events since a timestamp (RFC3339), unix time or relative duration (e.g. 10m)")
+    eventsCmd.Flags().StringVar(&eventsUntil, "until", "", "Stop after events up to this time instead of streaming")
+    eventsCmd.Flags().StringVar(&eventsFormat, "format", "table", "Output format (table or json)")
+    rootCmd.AddCommand(eventsCmd)
+}
+
+func eventsJournalPath() string {
+    return filepath.Join(eventsDir, "events.log")
+}
+
+// 이벤트를 저널에 추가 (여러 carte 프로세스가 동시에 기록하므로 잠금 안에서 로테이션과 쓰기)
+// 이벤트 기록 실패로 컨테이너 작업이 실패하지 않도록 경고만 출력
+func recordEvent(event lifecycleEvent) {
+    if err := appendEvent(event); err != nil {
+        fmt.Fprintf(os.Stderr, "Warning: failed to record %s %s event: %v\n", event.Type, event.Action, err)
+    }
+}
+
+func appendEvent(event lifecycleEvent) error {
+    if event.Time.IsZero() {
+        event.Time = time.Now().UTC()
+    }
+    data, err := json.Marshal(event)
+    if err != nil {
+        return err
+    }
+    data = append(data, '\n')
+
+    if err := os.MkdirAll(eventsDir, 0755); err != nil {
+        return fmt.Errorf("failed to create %s: %v", eventsDir, err)
+    }
+    lock, err := os.OpenFile(filepath.Join(eventsDir, "events.lock"), os.O_CREATE|os.O_RDWR, 0600)
+    if err != nil {
+        return fmt.Errorf("failed to open events lock: %v", err)
+    }
+    defer lock.Close()
+    if err := unix.Flock(int(lock.Fd()), unix.LOCK_EX); err != nil {
+        return fmt.Errorf("failed to lock events journal: %v", err)
+    }
+
+    path := eventsJournalPath()
+    if info, err := os.Stat(path); err == nil && info.Size() > 0 && info.Size()+int64(len(data)) > eventsMaxSize {
+        if err := os.Rename(path, path+".1"); err != nil {
+            return fmt.Errorf("failed to rotate events journal: %v", err)
+        }
+    }
+    file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+    if err != nil {
+        return fmt.Errorf("failed to open events journal: %v", err)
+    }
+    if _, err := file.Write(data); err != nil {
+        file.Close()
+        return fmt.Errorf("failed to write events journal: %v", err)
+    }
+    return file.Close()
+}
+
+// 컨테이너 이벤트 (ID와 이름은 상태 파일에서)
+func recordContainerEvent(containerName, action string, attributes map[string]string) {
+    event := lifecycleEvent{Type: "container", Action: action, ID: containerName, Name: containerName, Attributes: attributes}
+    if state, err := readContainerState(containerName); err == nil {
+        event.ID, event.Name = state.ID, state.Name
+        if state.Image != "" {
+            if event.Attributes == nil {
+                event.Attributes = map[string]string{}
+            }
+            event.Attributes["image"] = state.Image
+        }
+    }
+    recordEvent(event)
+}
+
+// --filter key=value[,key=value...]: 같은 키는 하나만 맞으면 되고 서로 다른 키는 모두 맞아야 함
+func parseEventFilters(specs []string) (map[string][]string, error) {
+    filter := map[string][]string{}
+    for _, spec := range specs {
+        for _, pair := range strings.Split(spec, ",") {
+            key, value, ok := strings.Cut(pair, "=")
+            if !ok || value == "" {
+                return nil, fmt.Errorf("invalid filter %q, expected key=value", pair)
+            }
+            switch key {
+            case "type", "name", "id", "event":
+            case "container":
+                key = "name" // docker 호환: 이름 또는 ID
+            default:
+                return nil, fmt.Errorf("unknown filter %q, expected type, name, id or event", key)
+            }
+            filter[key] = append(filter[key], value)
+        }
+    }
+    return filter, nil
+}
+
+func eventMatches(event *lifecycleEvent, filter map[string][]string) bool {
+    for key, values := range filter {
+        matched := false
+        for _, value := range values {
+            switch key {
+            case "type":
+                matched = event.Type == value
+            case "event":
+                matched = event.Action == value
+            case "id":
+                matched = value != "" && strings.HasPrefix(event.ID, value)
+            case "name":
+                // 이름이나 ID 접두사 (컨테이너를 가리키는 다른 방법과 같게)
+                matched = event.Name == value || len(value) >= 4 && strings.HasPrefix(event.ID, value)
+            }
+            if matched {
+                break
+            }
+        }
+        if !matched {
+            return false
+        }
+    }
+    return true
+}
+
+// --since가 있으면 저널에서 다시 보여 준 뒤 새 이벤트를 계속 출력 (--until이 지나면 종료)
+func showEvents(filter map[string][]string, since, until time.Time) error {
+    path := eventsJournalPath()
+    show := func(event *lifecycleEvent) {
+        if !since.IsZero() && event.Time.Before(since) {
+            return
+        }
+        if !until.IsZero() && event.Time.After(until) {
+            return
+        }
+        if eventMatches(event, filter) {
+            printEvent(event)
+        }
+    }
+
+    // 새 이벤트만 볼 때도 현재 저널 끝에서 시작해야 그 사이 기록된 것을 놓치지 않음
+    file, err := os.Open(path)
+    if err != nil && !os.IsNotExist(err) {
+        return fmt.Errorf("failed to open events journal: %v", err)
+    }
+    if since.IsZero() {
+        if file != nil {
+            if _, err := file.Seek(0, io.SeekEnd); err != nil {
+                file.Close()
+                return fmt.Errorf("failed to seek events journal: %v", err)
+            }
+        }
+    } else {
+        if err := replayEvents(path+".1", show); err != nil {
+            return err
+        }
+    }
+    if file != nil {
+        defer func() { file.Close() }()
+    }
+
+    var pending []byte
+    drain := func() error {
+        chunk, err := io.ReadAll(file)
+        if err != nil {
+            return fmt.Errorf("failed to read events journal: %v", err)
+        }
+        pending = append(pending, chunk...)
+        for {
+            i := bytes.IndexByte(pending, '\n')
+            if i < 0 {
+                break
+            }
+            var event lifecycleEvent
+            if err := json.Unmarshal(pending[:i], &event); err == nil {
+                show(&event)
+            }
+            pending = pending[i+1:]
+        }
+        return nil
+    }
+
+    for {
+        if file == nil {
+            if file, err = os.Open(path); err != nil && !os.IsNotExist(err) {
+                return fmt.Errorf("failed to open events journal: %v", err)
+            }
+        }
+        if file != nil {
+            if err := drain(); err != nil {
+                return err
+            }
+            // 로테이션으로 저널이 교체되었으면 이전 파일의 나머지를 읽고 새 파일을 처음부터 읽음
+            if info, err := os.Stat(path); err == nil {
+                if current, err := file.Stat(); err == nil && !os.SameFile(info, current) {
+                    if err := drain(); err != nil {
+                        return err
+                    }
+                    file.Close()
+                    file = nil
+                    pending = nil
+                    continue
+                }
+            }
+        }
+
+        if !until.IsZero() && time.Now().After(until) {
+            return nil
+        }
+        time.Sleep(eventsPollPeriod)
+    }
+}
+
+// 로테이션된 이전 저널 전체를 읽음
+func replayEvents(path string, show func(*lifecycleEvent)) error {
+    file, err := os.Open(path)
+    if os.IsNotExist(err) {
+        return nil
+    }
+    if err != nil {
+        return fmt.Errorf("failed to open events journal: %v", err)
+    }
+    defer file.Close()
+
+    scanner := bufio.NewScanner(file)
+    scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+    for scanner.Scan() {
+        var event lifecycleEvent
+        if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+            continue // 손상된 줄은 건너뜀
+        }
+        show(&event)
+    }
+    return scanner.Err()
+}
+
+// table: 시각 종류 동작 ID (속성...)
+func printEvent(event *lifecycleEvent) {
+    if eventsFormat == "json" {
+        data, err := json.Marshal(event)
+        if err == nil {
+            fmt.Println(string(data))
+        }
+        return
+    }
+
+    attributes := []string{}
+    if event.Name != "" && event.Name != event.ID {
+        attributes = append(attributes, "name="+event.Name)
+    }
+    keys := make([]string, 0, len(event.Attributes))
+    for key := range event.Attributes {
+        keys = append(keys, key)
+    }
+    sort.Strings(keys)
+    for _, key := range keys {
+        attributes = append(attributes, key+"="+event.Attributes[key])
+    }
+    line := fmt.Sprintf("%s %s %s %s", event.Time.Local().Format(time.RFC3339Nano), event.Type, event.Action, event.ID)
+    if len(attributes) > 0 {
+        line += " (" + strings.Join(attributes, ", ") + ")"
+    }
+    fmt.Println(line)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestParseEventFilters(t *testing.T) {
+    tests := []struct {
+        name    string
+        specs   []string
+        want    map[string][]string
+        wantErr bool
+    }{
+        {name: "none", want: map[string][]string{}},
+        {name: "single", specs: []string{"type=container"}, want: map[string][]string{"type": {"container"}}},
+        {
+            name:  "comma separated pairs",
+            specs: []string{"type=container,event=start"},
+            want:  map[string][]string{"type": {"container"}, "event": {"start"}},
+        },
+        {
+            name:  "repeated key across flags",
+            specs: []string{"event=start", "event=die,name=web"},
+            want:  map[string][]string{"event": {"start", "die"}, "name": {"web"}},
+        },
+        {
+            name:  "container is an alias of name",
+            specs: []string{"container=web,name=db"},
+            want:  map[string][]string{"name": {"web", "db"}},
+        },
+        {name: "value containing equals", specs: []string{"name=a=b"}, want: map[string][]string{"name": {"a=b"}}},
+        {name: "missing value", specs: []string{"type="}, wantErr: true},
+        {name: "missing equals", specs: []string{"type"}, wantErr: true},
+        {name: "trailing comma", specs: []string{"type=container,"}, wantErr: true},
+        {name: "unknown key after comma", specs: []string{"type=container,bogus=1"}, wantErr: true},
+        {name: "unknown key", specs: []string{"label=x"}, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := parseEventFilters(tt.specs)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parseEventFilters(%q) = %v, want error", tt.specs, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("parseEventFilters(%q) = %v, want %v", tt.specs, got, tt.want)
+            }
+        })
+    }
+}
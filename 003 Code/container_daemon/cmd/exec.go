@@ -0,0 +1,351 @@
+package cmd
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "runtime"
+    "strings"
+    "syscall"
+
+    "github.com/spf13/cobra"
+    "golang.org/x/sys/unix"
+)
+
+var (
+    execInteractive bool
+    execTTY         bool
+    execEnv         []string
+    execWorkdir     string
+    execUser        string
+)
+
+var execCmd = &cobra.Command{
+    Use:   "exec [container] [command...]",
+    Short: "Run a command in a running container",
+    Args:  cobra.MinimumNArgs(2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        exitCode, err := execInContainer(containerName, args[1:])
+        if err != nil {
+            return err
+        }
+        if exitCode != 0 {
+            os.Exit(exitCode)
+        }
+        return nil
+    },
+}
+
+func init() {
+    execCmd.Flags().BoolVarP(&execInteractive, "interactive", "i", false, "Keep STDIN open")
+    execCmd.Flags().BoolVarP(&execTTY, "tty", "t", false, "Allocate a pseudo-TTY")
+    execCmd.Flags().StringArrayVarP(&execEnv, "env", "e", nil, "Set environment variables (KEY=VAL)")
+    execCmd.Flags().StringVarP(&execWorkdir, "workdir", "w", "", "Working directory inside the container (defaults to the container's working directory)")
+    execCmd.Flags().StringVarP(&execUser, "user", "u", "", "Username or UID[:GID] (defaults to the container's user)")
+    // 컨테이너 이름 뒤의 플래그는 실행할 명령의 인자로 넘김
+    execCmd.Flags().SetInterspersed(false)
+    rootCmd.AddCommand(execCmd)
+}
+
+// 컨테이너에 새 프로세스를 실행하고 종료 코드를 반환
+func execInContainer(containerName string, args []string) (int, error) {
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return 0, err
+    }
+    if state.Status == statusPaused {
+        return 0, fmt.Errorf("container %s is paused, unpause it first", state.Name)
+    }
+    pid, err := readContainerPID(containerName)
+    if err != nil {
+        return 0, err
+    }
+    if err := syscall.Kill(pid, 0); err != nil {
+        return 0, fmt.Errorf("container %s is not running", containerName)
+    }
+
+    // 실행 파일과 사용자는 /proc/<pid>/root 를 통해 컨테이너 루트 기준으로 찾음
+    rootPath := fmt.Sprintf("/proc/%d/root", pid)
+
+    env, err := containerProcessEnv(pid)
+    if err != nil {
+        return 0, err
+    }
+    env = mergeEnv(env, execEnv)
+
+    path, err := lookPathInContainer(rootPath, args[0], env)
+    if err != nil {
+        return 0, err
+    }
+
+    // 컨테이너와 같은 사용자, 권한과 seccomp 프로파일 적용
+    seccomp, caps, user := seccompDefault, defaultCapabilities, ""
+    var process *processSpec
+    if opts, err := loadContainerConfig(containerName); err == nil {
+        seccomp, user, process = opts.Seccomp, opts.Process.User, &opts.Process
+        if opts.Capabilities != nil {
+            caps = opts.Capabilities
+        }
+    }
+    workdir, err := execWorkingDir(execWorkdir, process)
+    if err != nil {
+        return 0, err
+    }
+    if execUser != "" {
+        user = execUser
+    }
+    uid, gid, home, err := lookupContainerUser(rootPath, user)
+    if err != nil {
+        return 0, err
+    }
+    if execUser != "" {
+        env = mergeEnv(env, []string{"HOME=" + home})
+    }
+    filter, err := loadSeccompFilter(seccomp, caps)
+    if err != nil {
+        return 0, err
+    }
+    // nsinit이 컨테이너의 마운트 네임스페이스에 합류하여 컨테이너가 pivot_root 한 루트를 그대로 사용
+    config := &initConfig{
+        Path:         path,
+        Args:         args,
+        Env:          env,
+        Cwd:          workdir,
+        Uid:          uid,
+        Gid:          gid,
+        Capabilities: caps,
+        Seccomp:      filter,
+        Namespaces:   []specNamespace{{Type: "mount", Path: fmt.Sprintf("/proc/%d/ns/mnt", pid)}},
+    }
+
+    cmd, err := newInitCommand()
+    if err != nil {
+        return 0, err
+    }
+    cmd.SysProcAttr = &syscall.SysProcAttr{}
+
+    // 컨테이너와 같은 cgroup에 참여
+    if cgroupDir, err := processCgroupDir(pid); err == nil {
+        if cgroupFile, err := os.Open(cgroupDir); err == nil {
+            defer cgroupFile.Close()
+            cmd.SysProcAttr.UseCgroupFD = true
+            cmd.SysProcAttr.CgroupFD = int(cgroupFile.Fd())
+        }
+    }
+
+    var master, slave *os.File
+    if execTTY {
+        // 컨테이너에 devpts가 마운트되어 있으면 그 인스턴스에서 할당
+        ptsDir := filepath.Join(rootPath, "dev/pts")
+        if _, statErr := os.Stat(filepath.Join(ptsDir, "ptmx")); statErr == nil {
+            master, slave, err = openPty(filepath.Join(ptsDir, "ptmx"), ptsDir)
+        } else {
+            master, slave, err = openPty("/dev/ptmx", "/dev/pts")
+        }
+        if err != nil {
+            return 0, err
+        }
+        defer master.Close()
+
+        cmd.Stdin, cmd.Stdout, cmd.Stderr = slave, slave, slave
+        cmd.SysProcAttr.Setsid = true
+        cmd.SysProcAttr.Setctty = true
+        cmd.SysProcAttr.Ctty = 0
+    } else {
+        if execInteractive {
+            cmd.Stdin = os.Stdin
+        }
+        cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+    }
+
+    // setns는 호출한 스레드에만 적용되므로 전용 고루틴을 스레드에 고정하고,
+    // 네임스페이스가 바뀐 스레드는 고루틴 종료와 함께 버려지도록 UnlockOSThread를 호출하지 않음
+    startErr := make(chan error, 1)
+    go func() {
+        runtime.LockOSThread()
+        if err := joinContainerNamespaces(pid); err != nil {
+            startErr <- err
+            return
+        }
+        startErr <- startInit(cmd, config)
+    }()
+    err = <-startErr
+    if slave != nil {
+        // 부모 쪽 slave는 닫아야 자식 종료 시 master에서 EOF(EIO)를 받음
+        slave.Close()
+    }
+    if err != nil {
+        return 0, fmt.Errorf("failed to exec in container %s: %v", containerName, err)
+    }
+
+    outputDone := make(chan struct{})
+    if master != nil {
+        if execInteractive && isTerminal(os.Stdin) {
+            restore, err := setRawTerminal(os.Stdin)
+            if err == nil {
+                defer restore()
+            }
+            stopResize := forwardWinsize(os.Stdin, master)
+            defer stopResize()
+        }
+        if execInteractive {
+            go io.Copy(master, os.Stdin)
+        }
+        go func() {
+            io.Copy(os.Stdout, master)
+            close(outputDone)
+        }()
+    } else {
+        close(outputDone)
+    }
+
+    err = cmd.Wait()
+    <-outputDone
+    if exitErr, ok := err.(*exec.ExitError); ok {
+        return exitErr.ExitCode(), nil
+    }
+    if err != nil {
+        return 0, fmt.Errorf("failed to wait for exec process: %v", err)
+    }
+    return 0, nil
+}
+
+// /proc/<pid>/ns/* 를 통해 컨테이너 네임스페이스에 진입 (현재 스레드 기준)
+// 마운트 네임스페이스는 여기서 바꾸면 carte 실행 파일을 찾을 수 없으므로 nsinit이 합류
+func joinContainerNamespaces(pid int) error {
+    namespaces := []struct {
+        name string
+        flag int
+    }{
+        {"ipc", unix.CLONE_NEWIPC},
+        {"uts", unix.CLONE_NEWUTS},
+        {"net", unix.CLONE_NEWNET},
+        {"pid", unix.CLONE_NEWPID},
+    }
+
+    for _, ns := range namespaces {
+        target := fmt.Sprintf("/proc/%d/ns/%s", pid, ns.name)
+
+        // 이미 같은 네임스페이스면 건너뜀
+        targetInfo, err := os.Stat(target)
+        if err != nil {
+            return fmt.Errorf("failed to stat %s: %v", target, err)
+        }
+        if selfInfo, err := os.Stat("/proc/self/ns/" + ns.name); err == nil && os.SameFile(selfInfo, targetInfo) {
+            continue
+        }
+
+        nsFile, err := os.Open(target)
+        if err != nil {
+            return fmt.Errorf("failed to open %s: %v", target, err)
+        }
+        err = unix.Setns(int(nsFile.Fd()), ns.flag)
+        nsFile.Close()
+        if err != nil {
+            return fmt.Errorf("failed to join %s namespace: %v", ns.name, err)
+        }
+    }
+
+    return nil
+}
+
+// 컨테이너 프로세스의 환경 변수 읽기
+func containerProcessEnv(pid int) ([]string, error) {
+    data, err := os.ReadFile(fmt.Sprintf("/proc/%d/environ", pid))
+    if err != nil {
+        return nil, fmt.Errorf("failed to read container environment: %v", err)
+    }
+
+    var env []string
+    for _, kv := range bytes.Split(data, []byte{0}) {
+        if len(kv) > 0 {
+            env = append(env, string(kv))
+        }
+    }
+    return env, nil
+}
+
+// KEY=VAL 목록에 덮어쓸 값 적용 (KEY만 주어지면 호스트 값을 사용)
+func mergeEnv(env, overrides []string) []string {
+    merged := append([]string{}, env...)
+    for _, kv := range overrides {
+        key, value, found := strings.Cut(kv, "=")
+        if !found {
+            hostValue, ok := os.LookupEnv(key)
+            if !ok {
+                continue
+            }
+            value = hostValue
+        }
+
+        replaced := false
+        for i, existing := range merged {
+            if strings.HasPrefix(existing, key+"=") {
+                merged[i] = key + "=" + value
+                replaced = true
+            }
+        }
+        if !replaced {
+            merged = append(merged, key+"="+value)
+        }
+    }
+    return merged
+}
+
+// 컨테이너 루트 기준으로 PATH에서 실행 파일 검색
+func lookPathInContainer(rootPath, file string, env []string) (string, error) {
+    if strings.Contains(file, "/") {
+        return file, nil
+    }
+
+    pathEnv := "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+    for _, kv := range env {
+        if strings.HasPrefix(kv, "PATH=") {
+            pathEnv = strings.TrimPrefix(kv, "PATH=")
+        }
+    }
+
+    for _, dir := range filepath.SplitList(pathEnv) {
+        candidate := filepath.Join(dir, file)
+        // 이미지 안의 심볼릭 링크가 호스트 경로를 가리킬 수 있으므로 Lstat 사용
+        if info, err := os.Lstat(filepath.Join(rootPath, candidate)); err == nil && !info.IsDir() {
+            return candidate, nil
+        }
+    }
+    return "", fmt.Errorf("executable file %q not found in container $PATH", file)
+}
+
+// /proc/<pid>/cgroup 에서 cgroup v2 경로를 찾아 cgroupfs 상의 디렉토리 반환
+func processCgroupDir(pid int) (string, error) {
+    data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+    if err != nil {
+        return "", err
+    }
+    for _, line := range strings.Split(string(data), "\n") {
+        if strings.HasPrefix(line, "0::") {
+            return filepath.Join("/sys/fs/cgroup", strings.TrimPrefix(line, "0::")), nil
+        }
+    }
+    return "", fmt.Errorf("no cgroup v2 entry for PID %d", pid)
+}
+
+// 작업 디렉토리: -w, 컨테이너를 시작할 때 정한 작업 디렉토리(이미지의 WORKDIR 등), / 순
+func execWorkingDir(workdir string, process *processSpec) (string, error) {
+    if workdir == "" && process != nil {
+        workdir = process.Cwd
+    }
+    if workdir == "" {
+        return "/", nil
+    }
+    if !filepath.IsAbs(workdir) {
+        return "", fmt.Errorf("working directory %q must be absolute", workdir)
+    }
+    return filepath.Clean(workdir), nil
+}
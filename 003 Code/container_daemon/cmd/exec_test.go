@@ -0,0 +1,38 @@
+package cmd
+
+import "testing"
+
+func TestExecWorkingDir(t *testing.T) {
+    tests := []struct {
+        name    string
+        workdir string
+        process *processSpec
+        want    string
+        wantErr bool
+    }{
+        {name: "no config", want: "/"},
+        {name: "container working directory", process: &processSpec{Cwd: "/app"}, want: "/app"},
+        {name: "container without working directory", process: &processSpec{}, want: "/"},
+        {name: "flag overrides container", workdir: "/tmp", process: &processSpec{Cwd: "/app"}, want: "/tmp"},
+        {name: "flag is cleaned", workdir: "/srv//data/../www/", want: "/srv/www"},
+        {name: "relative flag", workdir: "app", process: &processSpec{Cwd: "/app"}, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := execWorkingDir(tt.workdir, tt.process)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("execWorkingDir(%q) = %s, want error", tt.workdir, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != tt.want {
+                t.Fatalf("execWorkingDir(%q) = %s, want %s", tt.workdir, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+
+    "github.com/spf13/cobra"
+)
+
+var exportOutput string
+
+var exportCmd = &cobra.Command{
+    Use:   "export [container]",
+    Short: "Export a container's filesystem as a tar archive",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        if exportOutput == "" || exportOutput == "-" {
+            if isTerminal(os.Stdout) {
+                return fmt.Errorf("refusing to write the archive to a terminal, use -o or redirect stdout")
+            }
+            return exportContainer(containerName, os.Stdout)
+        }
+        return exportContainerToFile(containerName, exportOutput)
+    },
+}
+
+func init() {
+    exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the archive to a file instead of stdout")
+    rootCmd.AddCommand(exportCmd)
+}
+
+// 컨테이너 루트 전체를 하나의 tar로 기록 (빈 이미지 대비 변경 = 모든 파일, carte 메타데이터 제외)
+func exportContainer(containerName string, w io.Writer) error {
+    root := filepath.Join("/CarteDaemon/container", containerName)
+    changes, err := computeChanges(root, map[string]fileMeta{})
+    if err != nil {
+        return err
+    }
+    return writeLayerTar(w, root, changes)
+}
+
+// 중간에 실패하면 불완전한 파일이 남지 않도록 임시 파일에 쓰고 rename
+func exportContainerToFile(containerName, output string) error {
+    tmp, err := os.CreateTemp(filepath.Dir(output), "."+filepath.Base(output)+".tmp")
+    if err != nil {
+        return fmt.Errorf("failed to create %s: %v", output, err)
+    }
+    defer os.Remove(tmp.Name())
+    defer tmp.Close()
+
+    if err := exportContainer(containerName, tmp); err != nil {
+        return fmt.Errorf("failed to export container: %v", err)
+    }
+    if err := tmp.Chmod(0644); err != nil {
+        return fmt.Errorf("failed to chmod %s: %v", output, err)
+    }
+    if err := tmp.Close(); err != nil {
+        return fmt.Errorf("failed to write %s: %v", output, err)
+    }
+    if err := os.Rename(tmp.Name(), output); err != nil {
+        return fmt.Errorf("failed to write %s: %v", output, err)
+    }
+    return nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+    "bufio"
+    "fmt"
+    "net"
+    "os"
+    "path/filepath"
+    "regexp"
+    "strings"
+)
+
+// 호스트의 resolv.conf에 쓸 수 있는 네임서버가 없을 때 사용하는 기본값
+var defaultDNSServers = []string{"8.8.8.8", "8.8.4.4"}
+
+var hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+// extraHost : /etc/hosts에 추가할 항목 (--add-host host:ip)
+type extraHost struct {
+    Host string `json:"host"`
+    IP   string `json:"ip"` // "host-gateway"면 컨테이너에서 본 호스트 IP
+}
+
+func validateHostname(hostname string) error {
+    if len(hostname) > 64 || !hostnamePattern.MatchString(hostname) {
+        return fmt.Errorf("invalid hostname %q", hostname)
+    }
+    return nil
+}
+
+// --add-host 해석: host:ip (IPv6 주소는 첫 ':' 뒤 전체)
+func parseExtraHost(spec string) (extraHost, error) {
+    host, ip, found := strings.Cut(spec, ":")
+    if !found || host == "" || ip == "" {
+        return extraHost{}, fmt.Errorf("invalid --add-host %q, expected host:ip", spec)
+    }
+    if err := validateHostname(host); err != nil {
+        return extraHost{}, err
+    }
+    if ip != "host-gateway" && net.ParseIP(ip) == nil {
+        return extraHost{}, fmt.Errorf("invalid IP address %q in --add-host %s", ip, spec)
+    }
+    return extraHost{Host: host, IP: ip}, nil
+}
+
+func parseDNSServers(servers []string) ([]string, error) {
+    for _, server := range servers {
+        if net.ParseIP(server) == nil {
+            return nil, fmt.Errorf("invalid --dns address %q", server)
+        }
+    }
+    return servers, nil
+}
+
+// 컨테이너의 /etc/hostname, /etc/hosts, /etc/resolv.conf를 메타데이터 디렉토리에 만들고
+// 컨테이너 루트에 읽기 전용으로 bind 할 마운트 목록을 반환
+func prepareEtcFiles(containerPath, containerName string, opts *startOptions, network *containerNetwork) ([]volumeMount, error) {
+    files := map[string]string{
+        "/etc/hostname":    opts.Hostname + "\n",
+        "/etc/hosts":       generateHostsFile(opts.Hostname, opts.ExtraHosts, network),
+        "/etc/resolv.conf": generateResolvConf(opts.DNS),
+    }
+
+    var mounts []volumeMount
+    for _, target := range []string{"/etc/hostname", "/etc/hosts", "/etc/resolv.conf"} {
+        source := filepath.Join(containerMetaDir(containerName), filepath.Base(target))
+        if err := os.WriteFile(source, []byte(files[target]), 0644); err != nil {
+            return nil, fmt.Errorf("failed to write %s: %v", source, err)
+        }
+        mountPoint, err := resolveInRoot(containerPath, target)
+        if err != nil {
+            return nil, fmt.Errorf("failed to resolve %s in container: %v", target, err)
+        }
+        mounts = append(mounts, volumeMount{Source: source, Target: target, ReadOnly: true, MountPoint: mountPoint})
+    }
+    return mounts, nil
+}
+
+func generateHostsFile(hostname string, extraHosts []extraHost, network *containerNetwork) string {
+    var b strings.Builder
+    b.WriteString("127.0.0.1\tlocalhost\n")
+    b.WriteString("::1\tlocalhost ip6-localhost ip6-loopback\n")
+    b.WriteString("fe00::0\tip6-localnet\n")
+    b.WriteString("ff00::0\tip6-mcastprefix\n")
+    b.WriteString("ff02::1\tip6-allnodes\n")
+    b.WriteString("ff02::2\tip6-allrouters\n")
+    fmt.Fprintf(&b, "%s\t%s\n", network.ContainerIP, hostname)
+    for _, host := range extraHosts {
+        ip := host.IP
+        if ip == "host-gateway" {
+            ip = network.HostIP
+        }
+        fmt.Fprintf(&b, "%s\t%s\n", ip, host.Host)
+    }
+    return b.String()
+}
+
+// --dns가 없으면 호스트 설정을 따르되, 컨테이너 네트워크 네임스페이스에서 닿지 않는
+// loopback 네임서버(systemd-resolved의 127.0.0.53 등)는 제외
+func generateResolvConf(dns []string) string {
+    var nameservers, others []string
+
+    if file, err := os.Open("/etc/resolv.conf"); err == nil {
+        scanner := bufio.NewScanner(file)
+        for scanner.Scan() {
+            line := strings.TrimSpace(scanner.Text())
+            fields := strings.Fields(line)
+            if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+                continue
+            }
+            if fields[0] == "nameserver" {
+                if len(fields) > 1 {
+                    if ip := net.ParseIP(fields[1]); ip != nil && !ip.IsLoopback() {
+                        nameservers = append(nameservers, fields[1])
+                    }
+                }
+                continue
+            }
+            others = append(others, line) // search, options 등
+        }
+        file.Close()
+    }
+
+    if len(dns) > 0 {
+        nameservers = dns
+    }
+    if len(nameservers) == 0 {
+        nameservers = defaultDNSServers
+    }
+
+    var b strings.Builder
+    for _, ns := range nameservers {
+        fmt.Fprintf(&b, "nameserver %s\n", ns)
+    }
+    for _, line := range others {
+        b.WriteString(line + "\n")
+    }
+    return b.String()
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestValidateHostname(t *testing.T) {
+    tests := []struct {
+        hostname string
+        wantErr  bool
+    }{
+        {hostname: "web"},
+        {hostname: "web-1.example.com"},
+        {hostname: "a"},
+        {hostname: strings.Repeat("a", 63)},
+        {hostname: "", wantErr: true},
+        {hostname: "-web", wantErr: true},
+        {hostname: "web-", wantErr: true},
+        {hostname: "web_1", wantErr: true},
+        {hostname: "web..example", wantErr: true},
+        {hostname: strings.Repeat("a", 64), wantErr: true},
+        {hostname: strings.Repeat("a.", 32) + "a", wantErr: true}, // 64자 초과
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.hostname, func(t *testing.T) {
+            err := validateHostname(tt.hostname)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("validateHostname(%q) error = %v, wantErr %v", tt.hostname, err, tt.wantErr)
+            }
+        })
+    }
+}
+
+func TestParseExtraHost(t *testing.T) {
+    tests := []struct {
+        spec    string
+        want    extraHost
+        wantErr bool
+    }{
+        {spec: "db:10.0.0.5", want: extraHost{Host: "db", IP: "10.0.0.5"}},
+        {spec: "v6:fe80::1", want: extraHost{Host: "v6", IP: "fe80::1"}},
+        {spec: "host.docker.internal:host-gateway", want: extraHost{Host: "host.docker.internal", IP: "host-gateway"}},
+        {spec: "db", wantErr: true},
+        {spec: ":10.0.0.5", wantErr: true},
+        {spec: "db:", wantErr: true},
+        {spec: "db:not-an-ip", wantErr: true},
+        {spec: "bad_host:10.0.0.5", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.spec, func(t *testing.T) {
+            got, err := parseExtraHost(tt.spec)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("parseExtraHost(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+            }
+            if !tt.wantErr && got != tt.want {
+                t.Fatalf("parseExtraHost(%q) = %+v, want %+v", tt.spec, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseDNSServers(t *testing.T) {
+    servers := []string{"1.1.1.1", "2606:4700:4700::1111"}
+    got, err := parseDNSServers(servers)
+    if err != nil || !reflect.DeepEqual(got, servers) {
+        t.Fatalf("parseDNSServers(%q) = %q, %v", servers, got, err)
+    }
+    if _, err := parseDNSServers([]string{"1.1.1.1", "dns.google"}); err == nil {
+        t.Fatalf("parseDNSServers() with a hostname succeeded")
+    }
+}
+
+func TestGenerateHostsFile(t *testing.T) {
+    network := &containerNetwork{HostIP: "10.88.0.1", ContainerIP: "10.88.0.2"}
+    hosts := []extraHost{{Host: "db", IP: "10.0.0.5"}, {Host: "gw", IP: "host-gateway"}}
+    got := generateHostsFile("web", hosts, network)
+
+    // 고정 항목 뒤에 컨테이너 자신과 --add-host 항목이 순서대로 붙음
+    want := "10.88.0.2\tweb\n10.0.0.5\tdb\n10.88.0.1\tgw\n"
+    if !strings.HasPrefix(got, "127.0.0.1\tlocalhost\n") || !strings.HasSuffix(got, want) {
+        t.Fatalf("generateHostsFile() = %q, want localhost first and suffix %q", got, want)
+    }
+}
+
+func TestGenerateResolvConf(t *testing.T) {
+    // --dns가 주어지면 호스트의 nameserver 대신 사용 (search, options는 호스트 설정 유지)
+    got := generateResolvConf([]string{"1.1.1.1", "9.9.9.9"})
+    var nameservers []string
+    for _, line := range strings.Split(got, "\n") {
+        if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "nameserver" {
+            nameservers = append(nameservers, fields[1])
+        }
+    }
+    if want := []string{"1.1.1.1", "9.9.9.9"}; !reflect.DeepEqual(nameservers, want) {
+        t.Fatalf("generateResolvConf() nameservers = %q, want %q", nameservers, want)
+    }
+    if !strings.HasPrefix(got, "nameserver 1.1.1.1\n") {
+        t.Fatalf("generateResolvConf() = %q, want nameservers first", got)
+    }
+}
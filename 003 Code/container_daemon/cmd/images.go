@@ -3,6 +3,9 @@ package cmd
 import (
     "fmt"
     "os"
+    "strings"
+    "text/tabwriter"
+    "time"
 
     "github.com/spf13/cobra"
 )
@@ -37,5 +40,32 @@ func listImages(imageDir string) error {
         }
     }
 
-    return nil
+    return listStoreImages()
+}
+
+// commit/import로 등록한 저장소 이미지 목록 (태그별로 한 줄, 태그가 없으면 <none>)
+func listStoreImages() error {
+    images, err := listImageRecords()
+    if err != nil {
+        return err
+    }
+    if len(images) == 0 {
+        return nil
+    }
+
+    fmt.Println()
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+    fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE")
+    for _, img := range images {
+        created := humanDuration(time.Since(img.Created)) + " ago"
+        repoTags := img.RepoTags
+        if len(repoTags) == 0 {
+            repoTags = []string{"<none>:<none>"}
+        }
+        for _, repoTag := range repoTags {
+            i := strings.LastIndex(repoTag, ":")
+            fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", repoTag[:i], repoTag[i+1:], shortID(img.ID), created, formatBytes(uint64(img.size())))
+        }
+    }
+    return w.Flush()
 }
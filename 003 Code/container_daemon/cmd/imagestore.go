@@ -0,0 +1,259 @@
+package cmd
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+    "time"
+
+    "golang.org/x/sys/unix"
+)
+
+// 레이어와 이미지 기록을 보관하는 저장소
+// layers/<sha256>.tar : 레이어 tar (내용의 sha256으로 이름을 붙여 같은 레이어는 한 번만 저장)
+// images/<id>.json    : 이미지 기록
+const imageStoreDir = "/CarteDaemon/image/store"
+
+var imageNamePattern = regexp.MustCompile(`^[a-z0-9]+([._/-][a-z0-9]+)*$`)
+
+// imageLayer : 이미지를 이루는 레이어 (아래에서 위 순서)
+type imageLayer struct {
+    Digest string `json:"digest"` // sha256:<hex>
+    Size   int64  `json:"size"`
+}
+
+// imageRecord : 저장소에 등록된 이미지
+type imageRecord struct {
+    ID        string       `json:"id"`
+    RepoTags  []string     `json:"repoTags,omitempty"`
+    Parent    string       `json:"parent,omitempty"`    // 부모 이미지 ID
+    Container string       `json:"container,omitempty"` // commit한 컨테이너 ID
+    Comment   string       `json:"comment,omitempty"`
+    Created   time.Time    `json:"created"`
+    Config    imageConfig  `json:"config"`
+    Layers    []imageLayer `json:"layers"`
+}
+
+func (img *imageRecord) size() int64 {
+    var total int64
+    for _, layer := range img.Layers {
+        total += layer.Size
+    }
+    return total
+}
+
+func imageRecordPath(id string) string {
+    return filepath.Join(imageStoreDir, "images", id+".json")
+}
+
+func layerPath(digest string) string {
+    return filepath.Join(imageStoreDir, "layers", strings.TrimPrefix(digest, "sha256:")+".tar")
+}
+
+// "name[:tag]" 해석, 태그가 없으면 latest
+func parseImageRef(ref string) (string, string, error) {
+    name, tag := ref, "latest"
+    // 레지스트리 포트(host:5000/name)와 구분하기 위해 마지막 '/' 뒤의 ':'만 태그로 봄
+    if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+        name, tag = ref[:i], ref[i+1:]
+    }
+    if !imageNamePattern.MatchString(name) {
+        return "", "", fmt.Errorf("invalid image name %q", name)
+    }
+    if tag == "" || len(tag) > 128 || !containerNamePattern.MatchString(tag) {
+        return "", "", fmt.Errorf("invalid image tag %q", tag)
+    }
+    return name, tag, nil
+}
+
+func ensureImageStore() error {
+    for _, dir := range []string{"images", "layers"} {
+        if err := os.MkdirAll(filepath.Join(imageStoreDir, dir), 0755); err != nil {
+            return fmt.Errorf("failed to create image store: %v", err)
+        }
+    }
+    return nil
+}
+
+// 저장소 전체를 잠금 (태그 이동과 등록이 겹치지 않도록)
+func lockImageStore() (func(), error) {
+    if err := ensureImageStore(); err != nil {
+        return nil, err
+    }
+    lock, err := os.OpenFile(filepath.Join(imageStoreDir, "store.lock"), os.O_CREATE|os.O_RDWR, 0600)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open image store lock: %v", err)
+    }
+    if err := unix.Flock(int(lock.Fd()), unix.LOCK_EX); err != nil {
+        lock.Close()
+        return nil, fmt.Errorf("failed to lock image store: %v", err)
+    }
+    return func() { lock.Close() }, nil
+}
+
+func listImageRecords() ([]*imageRecord, error) {
+    files, err := os.ReadDir(filepath.Join(imageStoreDir, "images"))
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to read image store: %v", err)
+    }
+    var images []*imageRecord
+    for _, file := range files {
+        if !strings.HasSuffix(file.Name(), ".json") {
+            continue
+        }
+        img, err := loadImageRecord(strings.TrimSuffix(file.Name(), ".json"))
+        if err != nil {
+            return nil, err
+        }
+        images = append(images, img)
+    }
+    sort.Slice(images, func(i, j int) bool { return images[i].Created.After(images[j].Created) })
+    return images, nil
+}
+
+func loadImageRecord(id string) (*imageRecord, error) {
+    data, err := os.ReadFile(imageRecordPath(id))
+    if err != nil {
+        return nil, fmt.Errorf("failed to read image %s: %v", id, err)
+    }
+    var img imageRecord
+    if err := json.Unmarshal(data, &img); err != nil {
+        return nil, fmt.Errorf("failed to parse image %s: %v", id, err)
+    }
+    return &img, nil
+}
+
+// 태그(name:tag), 전체 ID 또는 유일한 ID 접두사로 이미지 찾기
+func lookupImage(ref string) (*imageRecord, error) {
+    images, err := listImageRecords()
+    if err != nil {
+        return nil, err
+    }
+    tagged := ref
+    if name, tag, err := parseImageRef(ref); err == nil {
+        tagged = name + ":" + tag
+    }
+
+    var byPrefix []*imageRecord
+    for _, img := range images {
+        if img.ID == ref {
+            return img, nil
+        }
+        for _, repoTag := range img.RepoTags {
+            if repoTag == tagged {
+                return img, nil
+            }
+        }
+        if strings.HasPrefix(img.ID, ref) {
+            byPrefix = append(byPrefix, img)
+        }
+    }
+    switch len(byPrefix) {
+    case 0:
+        return nil, fmt.Errorf("no such image: %s", ref)
+    case 1:
+        return byPrefix[0], nil
+    }
+    return nil, fmt.Errorf("image ID prefix %q is ambiguous", ref)
+}
+
+// 레이어 tar를 만들어 저장소에 넣고 digest를 반환 (write가 tar 스트림을 씀)
+// 같은 내용이면 같은 파일 이름이 되므로 잠그지 않고 rename으로 교체
+func storeLayer(write func(w io.Writer) error) (imageLayer, error) {
+    if err := ensureImageStore(); err != nil {
+        return imageLayer{}, err
+    }
+
+    tmp, err := os.CreateTemp(filepath.Join(imageStoreDir, "layers"), ".layer-*.tar")
+    if err != nil {
+        return imageLayer{}, fmt.Errorf("failed to create layer file: %v", err)
+    }
+    defer os.Remove(tmp.Name())
+    defer tmp.Close()
+
+    hash := sha256.New()
+    counter := &countingWriter{w: io.MultiWriter(tmp, hash)}
+    if err := write(counter); err != nil {
+        return imageLayer{}, err
+    }
+    if err := tmp.Sync(); err != nil {
+        return imageLayer{}, fmt.Errorf("failed to write layer: %v", err)
+    }
+
+    layer := imageLayer{Digest: "sha256:" + hex.EncodeToString(hash.Sum(nil)), Size: counter.n}
+    if err := os.Rename(tmp.Name(), layerPath(layer.Digest)); err != nil {
+        return imageLayer{}, fmt.Errorf("failed to store layer: %v", err)
+    }
+    return layer, nil
+}
+
+type countingWriter struct {
+    w io.Writer
+    n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+    n, err := c.w.Write(p)
+    c.n += int64(n)
+    return n, err
+}
+
+// 이미지 등록 (ID는 설정과 레이어 목록의 sha256), repoTag가 다른 이미지에 붙어 있으면 옮겨 옴
+func registerImage(img *imageRecord, repoTag string) error {
+    unlock, err := lockImageStore()
+    if err != nil {
+        return err
+    }
+    defer unlock()
+
+    content, err := json.Marshal(struct {
+        Parent  string       `json:"parent"`
+        Created time.Time    `json:"created"`
+        Config  imageConfig  `json:"config"`
+        Layers  []imageLayer `json:"layers"`
+    }{img.Parent, img.Created, img.Config, img.Layers})
+    if err != nil {
+        return err
+    }
+    sum := sha256.Sum256(content)
+    img.ID = hex.EncodeToString(sum[:])
+    img.RepoTags = []string{repoTag}
+
+    others, err := listImageRecords()
+    if err != nil {
+        return err
+    }
+    for _, other := range others {
+        kept := other.RepoTags[:0]
+        for _, tag := range other.RepoTags {
+            if tag != repoTag {
+                kept = append(kept, tag)
+            }
+        }
+        if len(kept) != len(other.RepoTags) {
+            other.RepoTags = kept
+            if err := saveImageRecord(other); err != nil {
+                return err
+            }
+        }
+    }
+    return saveImageRecord(img)
+}
+
+func saveImageRecord(img *imageRecord) error {
+    data, err := json.MarshalIndent(img, "", "  ")
+    if err != nil {
+        return err
+    }
+    return writeFileAtomic(imageRecordPath(img.ID), data, 0644)
+}
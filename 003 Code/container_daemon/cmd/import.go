@@ -0,0 +1,121 @@
+package cmd
+
+import (
+    "archive/tar"
+    "bufio"
+    "compress/gzip"
+    "fmt"
+    "io"
+    "os"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+var (
+    importChanges []string
+    importMessage string
+)
+
+var importCmd = &cobra.Command{
+    Use:   "import [file|-] [name:tag]",
+    Short: "Create a single-layer image from a root filesystem tarball",
+    Args:  cobra.ExactArgs(2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        return importImage(args[0], args[1])
+    },
+}
+
+func init() {
+    importCmd.Flags().StringArrayVarP(&importChanges, "change", "c", nil, "Apply a Dockerfile instruction to the image config (CMD, ENTRYPOINT, ENV, WORKDIR, USER, EXPOSE, STOPSIGNAL)")
+    importCmd.Flags().StringVarP(&importMessage, "message", "m", "", "Commit message for the imported image")
+    rootCmd.AddCommand(importCmd)
+}
+
+func importImage(source, ref string) error {
+    name, tag, err := parseImageRef(ref)
+    if err != nil {
+        return err
+    }
+    config := &imageConfig{}
+    for _, change := range importChanges {
+        if err := applyImageChange(config, change); err != nil {
+            return err
+        }
+    }
+
+    input := os.Stdin
+    if source != "-" {
+        if input, err = os.Open(source); err != nil {
+            return fmt.Errorf("failed to open %s: %v", source, err)
+        }
+        defer input.Close()
+    }
+    archive, err := decompressArchive(input)
+    if err != nil {
+        return err
+    }
+
+    layer, err := storeLayer(func(w io.Writer) error {
+        return copyTarArchive(w, archive)
+    })
+    if err != nil {
+        return fmt.Errorf("failed to import %s: %v", source, err)
+    }
+
+    img := &imageRecord{
+        Comment: importMessage,
+        Created: time.Now().UTC(),
+        Config:  *config,
+        Layers:  []imageLayer{layer},
+    }
+    if err := registerImage(img, name+":"+tag); err != nil {
+        return err
+    }
+    recordEvent(lifecycleEvent{Type: "image", Action: "import", ID: img.ID, Name: name + ":" + tag})
+    fmt.Println("sha256:" + img.ID)
+    return nil
+}
+
+// gzip으로 압축된 tar도 받음 (저장하는 레이어는 압축을 푼 tar)
+func decompressArchive(r io.Reader) (io.Reader, error) {
+    buffered := bufio.NewReader(r)
+    magic, err := buffered.Peek(2)
+    if err != nil {
+        return nil, fmt.Errorf("failed to read archive: %v", err)
+    }
+    if magic[0] == 0x1f && magic[1] == 0x8b {
+        gz, err := gzip.NewReader(buffered)
+        if err != nil {
+            return nil, fmt.Errorf("failed to decompress archive: %v", err)
+        }
+        return gz, nil
+    }
+    return buffered, nil
+}
+
+// tar 형식을 확인하며 그대로 복사 (잘못된 파일을 이미지로 등록하지 않도록 끝까지 읽어 봄)
+func copyTarArchive(w io.Writer, r io.Reader) error {
+    tee := io.TeeReader(r, w)
+    reader := tar.NewReader(tee)
+    entries := 0
+    for {
+        _, err := reader.Next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return fmt.Errorf("invalid tar archive: %v", err)
+        }
+        if _, err := io.Copy(io.Discard, reader); err != nil {
+            return fmt.Errorf("invalid tar archive: %v", err)
+        }
+        entries++
+    }
+    if entries == 0 {
+        return fmt.Errorf("archive is empty")
+    }
+    // 끝 표시 뒤의 나머지 블록까지 복사
+    _, err := io.Copy(io.Discard, tee)
+    return err
+}
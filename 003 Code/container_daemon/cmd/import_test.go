@@ -0,0 +1,131 @@
+package cmd
+
+import (
+    "archive/tar"
+    "bytes"
+    "compress/gzip"
+    "io"
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func testTarArchive(t *testing.T, files map[string]string) []byte {
+    t.Helper()
+    var buf bytes.Buffer
+    tw := tar.NewWriter(&buf)
+    for name, content := range files {
+        if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}); err != nil {
+            t.Fatal(err)
+        }
+        tw.Write([]byte(content))
+    }
+    if err := tw.Close(); err != nil {
+        t.Fatal(err)
+    }
+    return buf.Bytes()
+}
+
+func TestDecompressArchive(t *testing.T) {
+    archive := testTarArchive(t, map[string]string{"etc/hostname": "web\n"})
+    var gzipped bytes.Buffer
+    gz := gzip.NewWriter(&gzipped)
+    gz.Write(archive)
+    gz.Close()
+
+    for name, input := range map[string][]byte{"plain": archive, "gzip": gzipped.Bytes()} {
+        t.Run(name, func(t *testing.T) {
+            r, err := decompressArchive(bytes.NewReader(input))
+            if err != nil {
+                t.Fatal(err)
+            }
+            got, err := io.ReadAll(r)
+            if err != nil || !bytes.Equal(got, archive) {
+                t.Fatalf("decompressArchive() read %d bytes, %v, want the %d byte tar", len(got), err, len(archive))
+            }
+        })
+    }
+
+    if _, err := decompressArchive(bytes.NewReader(nil)); err == nil {
+        t.Fatalf("decompressArchive() of empty input succeeded")
+    }
+    // gzip 헤더가 깨졌으면 오류
+    if _, err := decompressArchive(bytes.NewReader([]byte{0x1f, 0x8b, 0})); err == nil {
+        t.Fatalf("decompressArchive() of a broken gzip header succeeded")
+    }
+}
+
+func TestCopyTarArchive(t *testing.T) {
+    archive := testTarArchive(t, map[string]string{"a": "1", "b": strings.Repeat("x", 1000)})
+    // tar 끝 표시 뒤에 블록이 더 붙어 있어도 그대로 복사
+    padded := append(append([]byte{}, archive...), make([]byte, 10240)...)
+
+    for name, input := range map[string][]byte{"archive": archive, "padded": padded} {
+        t.Run(name, func(t *testing.T) {
+            var out bytes.Buffer
+            if err := copyTarArchive(&out, bytes.NewReader(input)); err != nil {
+                t.Fatal(err)
+            }
+            if !bytes.Equal(out.Bytes(), input) {
+                t.Fatalf("copyTarArchive() wrote %d bytes, want the %d input bytes", out.Len(), len(input))
+            }
+        })
+    }
+
+    tests := map[string][]byte{
+        "empty archive": testTarArchive(t, nil),
+        "not a tar":     []byte(strings.Repeat("hello world\n", 100)),
+        "truncated":     archive[:len(archive)/2],
+    }
+    for name, input := range tests {
+        t.Run(name, func(t *testing.T) {
+            if err := copyTarArchive(io.Discard, bytes.NewReader(input)); err == nil {
+                t.Fatalf("copyTarArchive() of %s succeeded", name)
+            }
+        })
+    }
+}
+
+// export는 빈 기준 목록과 비교해 루트 전체를 레이어로 씀
+func TestExportLayerContents(t *testing.T) {
+    root := t.TempDir()
+    if err := os.MkdirAll(filepath.Join(root, "etc"), 0755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(filepath.Join(root, "etc", "hostname"), []byte("web\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Symlink("etc/hostname", filepath.Join(root, "hostname")); err != nil {
+        t.Fatal(err)
+    }
+
+    changes, err := computeChanges(root, map[string]fileMeta{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    var buf bytes.Buffer
+    if err := writeLayerTar(&buf, root, changes); err != nil {
+        t.Fatal(err)
+    }
+    if err := copyTarArchive(io.Discard, bytes.NewReader(buf.Bytes())); err != nil {
+        t.Fatalf("exported layer is not importable: %v", err)
+    }
+
+    var names []string
+    tr := tar.NewReader(&buf)
+    for {
+        header, err := tr.Next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            t.Fatal(err)
+        }
+        names = append(names, header.Name)
+    }
+    if want := []string{"etc/", "etc/hostname", "hostname"}; !reflect.DeepEqual(names, want) {
+        t.Fatalf("exported entries = %v, want %v", names, want)
+    }
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "os/signal"
+    "syscall"
+
+    "golang.org/x/sys/unix"
+)
+
+// --init: nsinit이 명령으로 exec 하지 않고 PID 1로 남아 명령을 자식으로 실행
+// 받은 신호를 자식 프로세스 그룹에 전달하고, 고아 프로세스를 거두며, 자식의 종료 상태로 끝남
+func runContainerInit(config *initConfig) error {
+    // 자식을 띄우기 전에 신호를 받기 시작해야 그 사이에 온 신호를 놓치지 않음
+    signals := make(chan os.Signal, 32)
+    signal.Notify(signals)
+
+    attr := &syscall.ProcAttr{
+        Env:   config.Env,
+        Files: []uintptr{0, 1, 2},
+        Sys: &syscall.SysProcAttr{
+            Setpgid: true, // 신호를 그룹 단위로 전달
+        },
+    }
+    if config.Console {
+        // 자식 그룹을 터미널의 foreground로 (Ctrl-C 등이 자식에게 감)
+        attr.Sys.Foreground = true
+        attr.Sys.Ctty = 0
+    }
+    child, err := syscall.ForkExec(config.Path, config.Args, attr)
+    if err != nil {
+        return fmt.Errorf("failed to exec %s: %v", config.Path, err)
+    }
+
+    for sig := range signals {
+        switch sig {
+        case unix.SIGCHLD:
+            if status, exited := reapChildren(child); exited {
+                os.Exit(initExitCode(status))
+            }
+        case unix.SIGURG:
+            // Go 런타임이 선점용으로 스스로 보내는 신호
+        default:
+            if err := syscall.Kill(-child, sig.(syscall.Signal)); err == syscall.ESRCH {
+                // 그룹을 떠난 경우 자식에게 직접
+                syscall.Kill(child, sig.(syscall.Signal))
+            }
+        }
+    }
+    return nil
+}
+
+// 종료된 자식을 모두 거둠 (다른 부모가 끝나 넘겨받은 고아 포함), 주 자식이 끝났으면 그 상태를 반환
+func reapChildren(child int) (syscall.WaitStatus, bool) {
+    var childStatus syscall.WaitStatus
+    childExited := false
+    for {
+        var status syscall.WaitStatus
+        pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+        if err == syscall.EINTR {
+            continue
+        }
+        if pid <= 0 || err != nil {
+            return childStatus, childExited
+        }
+        if pid == child {
+            childStatus, childExited = status, true
+        }
+    }
+}
+
+// 시그널로 끝났으면 128+시그널 번호
+func initExitCode(status syscall.WaitStatus) int {
+    if status.Signaled() {
+        return 128 + int(status.Signal())
+    }
+    return status.ExitStatus()
+}
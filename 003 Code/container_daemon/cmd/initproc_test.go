@@ -0,0 +1,74 @@
+package cmd
+
+import (
+    "os/exec"
+    "syscall"
+    "testing"
+    "time"
+)
+
+func TestInitExitCode(t *testing.T) {
+    tests := []struct {
+        name   string
+        status syscall.WaitStatus // wait(2) 상태 값 (종료 코드는 상위 바이트, 시그널은 하위 7비트)
+        want   int
+    }{
+        {name: "exit 0", status: 0, want: 0},
+        {name: "exit 3", status: 3 << 8, want: 3},
+        {name: "exit 255", status: 255 << 8, want: 255},
+        {name: "SIGTERM", status: syscall.WaitStatus(syscall.SIGTERM), want: 128 + 15},
+        {name: "SIGKILL", status: syscall.WaitStatus(syscall.SIGKILL), want: 128 + 9},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := initExitCode(tt.status); got != tt.want {
+                t.Fatalf("initExitCode(%#x) = %d, want %d", uint32(tt.status), got, tt.want)
+            }
+        })
+    }
+}
+
+func TestReapChildren(t *testing.T) {
+    sh, err := exec.LookPath("sh")
+    if err != nil {
+        t.Skip("sh not found")
+    }
+    // 주 자식 외의 자식(넘겨받은 고아 역할)도 함께 거둬야 함
+    other, err := syscall.ForkExec(sh, []string{"sh", "-c", "exit 1"}, &syscall.ProcAttr{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    child, err := syscall.ForkExec(sh, []string{"sh", "-c", "exit 4"}, &syscall.ProcAttr{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    deadline := time.Now().Add(5 * time.Second)
+    for {
+        status, exited := reapChildren(child)
+        if exited {
+            if got := initExitCode(status); got != 4 {
+                t.Fatalf("reapChildren() exit code = %d, want 4", got)
+            }
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("reapChildren() did not report the child exit")
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+
+    // 다른 자식도 좀비로 남지 않아야 함
+    deadline = time.Now().Add(5 * time.Second)
+    for {
+        if _, err := syscall.Wait4(other, nil, syscall.WNOHANG, nil); err == syscall.ECHILD {
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("reapChildren() left pid %d unreaped", other)
+        }
+        reapChildren(child)
+        time.Sleep(10 * time.Millisecond)
+    }
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+
+    "github.com/spf13/cobra"
+)
+
+// containerInspect : inspect 명령이 출력하는 컨테이너 정보
+type containerInspect struct {
+    ID      string            `json:"id"`
+    Name    string            `json:"name"`
+    Path    string            `json:"path"`
+    State   *containerState   `json:"state"`
+    Running bool              `json:"running"`
+    Pid     int               `json:"pid,omitempty"`
+    Config  *startOptions     `json:"config,omitempty"`
+    Network *containerNetwork `json:"network,omitempty"`
+    Mounts  []volumeMount     `json:"mounts,omitempty"`
+}
+
+var inspectCmd = &cobra.Command{
+    Use:   "inspect [container]",
+    Short: "Display detailed information on a container",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        info, err := inspectContainer(containerName)
+        if err != nil {
+            return err
+        }
+        data, err := json.MarshalIndent(info, "", "  ")
+        if err != nil {
+            return err
+        }
+        fmt.Println(string(data))
+        return nil
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(inspectCmd)
+}
+
+func inspectContainer(containerName string) (*containerInspect, error) {
+    containerPath := "/CarteDaemon/container/" + containerName
+    if _, err := os.Stat(containerPath); os.IsNotExist(err) {
+        return nil, fmt.Errorf("container %s does not exist", containerName)
+    }
+
+    info := &containerInspect{Name: containerName, Path: containerPath}
+
+    // 한 번도 실행하지 않은 컨테이너는 실행 설정이 없음
+    if opts, err := loadContainerConfig(containerName); err == nil {
+        info.Config = opts
+    }
+
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return nil, err
+    }
+    info.ID = state.ID
+    info.Name = state.Name
+    info.State = state
+    info.Running = state.isActive()
+    if info.Running {
+        info.Pid = state.Pid
+        info.Network, _ = loadContainerNetwork(containerName)
+        info.Mounts, _ = loadContainerMounts(containerName)
+    }
+
+    return info, nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+    "fmt"
+    "strconv"
+    "strings"
+    "syscall"
+    "time"
+
+    "github.com/spf13/cobra"
+    "golang.org/x/sys/unix"
+)
+
+// SIGKILL 외의 신호를 보낸 뒤 PID 1이 끝났는지 확인하는 시간
+const killExitGrace = time.Second
+
+var killSignal string
+
+var killCmd = &cobra.Command{
+    Use:   "kill [container]",
+    Short: "Send a signal to a running container",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        sig, err := parseSignal(killSignal)
+        if err != nil {
+            return err
+        }
+        return killContainer(containerName, sig)
+    },
+}
+
+func init() {
+    killCmd.Flags().StringVarP(&killSignal, "signal", "s", "SIGKILL", "Signal to send to the container")
+    rootCmd.AddCommand(killCmd)
+}
+
+// 컨테이너 PID 1에 신호 전달
+// PID 1이 신호로 끝나면 stop과 같이 남은 프로세스를 정리하고 종료를 기록한 뒤 네트워크와 cgroup을 정리
+func killContainer(containerName string, sig syscall.Signal) error {
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return err
+    }
+    if !state.isActive() {
+        return fmt.Errorf("container %s is not running", state.Name)
+    }
+    // SIGKILL 외의 신호는 멈춘 프로세스에 전달되지 않음
+    if state.Status == statusPaused && sig != syscall.SIGKILL {
+        return fmt.Errorf("container %s is paused, unpause it first", state.Name)
+    }
+
+    if err := syscall.Kill(state.Pid, sig); err != nil {
+        return fmt.Errorf("failed to send %s to container %s: %v", unixSignalName(sig), state.Name, err)
+    }
+    fmt.Printf("Sent %s to container %s (PID %d)\n", unixSignalName(sig), state.Name, state.Pid)
+    recordContainerEvent(containerName, "kill", map[string]string{"signal": unixSignalName(sig)})
+
+    // SIGKILL은 끝날 때까지 기다리고, 다른 신호는 처리기가 있을 수 있으므로 잠시만 확인
+    wait := killExitGrace
+    if sig == syscall.SIGKILL {
+        wait = 10 * time.Second
+    }
+    if !waitProcessExit(state.Pid, state.PidStartTime, wait) {
+        if sig == syscall.SIGKILL {
+            return fmt.Errorf("container %s did not exit after SIGKILL", state.Name)
+        }
+        return nil
+    }
+    if err := reapContainer(containerName, state.Pid, sig); err != nil {
+        return err
+    }
+    cleanupContainer(containerName)
+    return nil
+}
+
+// "SIGTERM", "TERM", "term", "15" 모두 허용
+func parseSignal(name string) (syscall.Signal, error) {
+    name = strings.TrimSpace(name)
+    if n, err := strconv.Atoi(name); err == nil {
+        if n <= 0 || n > 64 {
+            return 0, fmt.Errorf("invalid signal number %d", n)
+        }
+        return syscall.Signal(n), nil
+    }
+    name = strings.ToUpper(name)
+    if !strings.HasPrefix(name, "SIG") {
+        name = "SIG" + name
+    }
+    sig := unix.SignalNum(name)
+    if sig == 0 {
+        return 0, fmt.Errorf("unknown signal %q", name)
+    }
+    return sig, nil
+}
+
+func unixSignalName(sig syscall.Signal) string {
+    if name := unix.SignalName(sig); name != "" {
+        return name
+    }
+    return strconv.Itoa(int(sig))
+}
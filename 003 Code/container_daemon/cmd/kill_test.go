@@ -0,0 +1,78 @@
+package cmd
+
+import (
+    "os/exec"
+    "syscall"
+    "testing"
+    "time"
+)
+
+func TestParseSignal(t *testing.T) {
+    tests := []struct {
+        name    string
+        want    syscall.Signal
+        wantErr bool
+    }{
+        {name: "SIGTERM", want: syscall.SIGTERM},
+        {name: "term", want: syscall.SIGTERM},
+        {name: "Kill", want: syscall.SIGKILL},
+        {name: " hup ", want: syscall.SIGHUP},
+        {name: "9", want: syscall.SIGKILL},
+        {name: "64", want: syscall.Signal(64)},
+        {name: "0", wantErr: true},
+        {name: "65", wantErr: true},
+        {name: "-1", wantErr: true},
+        {name: "SIGFOO", wantErr: true},
+        {name: "", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := parseSignal(tt.name)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("parseSignal(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+            }
+            if !tt.wantErr && got != tt.want {
+                t.Fatalf("parseSignal(%q) = %d, want %d", tt.name, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestUnixSignalName(t *testing.T) {
+    if got := unixSignalName(syscall.SIGKILL); got != "SIGKILL" {
+        t.Fatalf("unixSignalName(SIGKILL) = %q, want \"SIGKILL\"", got)
+    }
+    // 이름 없는 실시간 시그널은 번호
+    if got := unixSignalName(syscall.Signal(40)); got != "40" {
+        t.Fatalf("unixSignalName(40) = %q, want \"40\"", got)
+    }
+    // 이름으로 바꾼 값은 다시 같은 시그널로 해석되어야 함 (stop이 kill로 넘기는 값)
+    for _, sig := range []syscall.Signal{syscall.SIGTERM, syscall.SIGINT, syscall.SIGUSR1, syscall.Signal(40)} {
+        if got, err := parseSignal(unixSignalName(sig)); err != nil || got != sig {
+            t.Fatalf("parseSignal(unixSignalName(%d)) = %d, %v", sig, got, err)
+        }
+    }
+}
+
+func TestWaitProcessExit(t *testing.T) {
+    cmd := exec.Command("sleep", "10")
+    if err := cmd.Start(); err != nil {
+        t.Skipf("cannot run sleep: %v", err)
+    }
+    defer cmd.Wait()
+    pid := cmd.Process.Pid
+    startTime, err := processStartTime(pid)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if waitProcessExit(pid, startTime, 200*time.Millisecond) {
+        t.Fatalf("waitProcessExit() = true for a running process")
+    }
+    // 거두기 전의 좀비도 종료된 것으로 봄
+    cmd.Process.Signal(syscall.SIGTERM)
+    if !waitProcessExit(pid, startTime, 5*time.Second) {
+        t.Fatalf("waitProcessExit() = false after the process was killed")
+    }
+}
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+    "archive/tar"
+    "fmt"
+    "io"
+    "io/fs"
+    "os"
+    "path"
+    "path/filepath"
+    "sort"
+    "strings"
+    "syscall"
+    "time"
+
+    "golang.org/x/sys/unix"
+)
+
+// OCI/AUFS 방식의 삭제 표시: 같은 디렉토리의 .wh.<name>은 name 삭제, .wh..wh..opq는 하위 레이어의 내용 전체를 가림
+const (
+    whiteoutPrefix = ".wh."
+    whiteoutOpaque = ".wh..wh..opq"
+)
+
+// 변경 종류 (docker diff 표기와 같음)
+const (
+    changeAdded    = "A"
+    changeModified = "C"
+    changeDeleted  = "D"
+)
+
+// fileChange : 이미지 대비 컨테이너 파일시스템의 변경
+type fileChange struct {
+    Kind string `json:"kind"`
+    Path string `json:"path"`
+}
+
+// fileMeta : 변경 여부 판단에 쓰는 파일 속성 (내용은 크기와 수정 시각으로 비교)
+type fileMeta struct {
+    Mode     os.FileMode
+    Uid      int
+    Gid      int
+    Size     int64
+    ModTime  int64
+    Linkname string
+    Rdev     uint64
+}
+
+func metaFromHeader(h *tar.Header) fileMeta {
+    return fileMeta{
+        Mode:     h.FileInfo().Mode(),
+        Uid:      h.Uid,
+        Gid:      h.Gid,
+        Size:     h.Size,
+        ModTime:  h.ModTime.Unix(),
+        Linkname: h.Linkname,
+        Rdev:     unix.Mkdev(uint32(h.Devmajor), uint32(h.Devminor)),
+    }
+}
+
+func metaFromFile(fullPath string, info fs.FileInfo) fileMeta {
+    meta := fileMeta{Mode: info.Mode(), Size: info.Size(), ModTime: info.ModTime().Unix()}
+    if st, ok := info.Sys().(*syscall.Stat_t); ok {
+        meta.Uid, meta.Gid, meta.Rdev = int(st.Uid), int(st.Gid), uint64(st.Rdev)
+    }
+    if info.Mode()&os.ModeSymlink != 0 {
+        meta.Linkname, _ = os.Readlink(fullPath)
+    }
+    return meta
+}
+
+func (m fileMeta) differs(other fileMeta) bool {
+    if m.Mode != other.Mode || m.Uid != other.Uid || m.Gid != other.Gid {
+        return true
+    }
+    switch {
+    case m.Mode.IsRegular():
+        return m.Size != other.Size || m.ModTime != other.ModTime
+    case m.Mode.IsDir():
+        // 하위 항목이 추가되거나 삭제되면 디렉토리 수정 시각이 바뀜
+        return m.ModTime != other.ModTime
+    case m.Mode&os.ModeSymlink != 0:
+        return m.Linkname != other.Linkname
+    case m.Mode&os.ModeDevice != 0:
+        return m.Rdev != other.Rdev
+    }
+    return false
+}
+
+// 레이어를 차례로 겹친 결과의 파일 목록 ("/etc/passwd" 형식의 경로 -> 속성)
+func imageManifest(img *imageRecord) (map[string]fileMeta, error) {
+    manifest := map[string]fileMeta{}
+    for _, layer := range img.Layers {
+        if err := applyLayerManifest(manifest, layerPath(layer.Digest)); err != nil {
+            return nil, fmt.Errorf("failed to read layer %s: %v", layer.Digest, err)
+        }
+    }
+    return manifest, nil
+}
+
+func applyLayerManifest(manifest map[string]fileMeta, tarPath string) error {
+    file, err := os.Open(tarPath)
+    if err != nil {
+        return err
+    }
+    defer file.Close()
+
+    // 같은 레이어 안에서는 순서와 상관없이 삭제 표시가 하위 레이어에만 적용되도록 모아 두었다가 반영
+    added := map[string]fileMeta{}
+    var removed, opaque []string
+
+    reader := tar.NewReader(file)
+    for {
+        header, err := reader.Next()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return err
+        }
+
+        name := path.Join("/", header.Name)
+        if name == "/" {
+            continue // "./" 항목 (루트 자체는 비교하지 않음)
+        }
+        dir, base := path.Split(name)
+        switch {
+        case base == whiteoutOpaque:
+            opaque = append(opaque, path.Clean(dir))
+        case strings.HasPrefix(base, whiteoutPrefix):
+            removed = append(removed, path.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)))
+        case header.Typeflag == tar.TypeLink:
+            // 하드 링크는 대상과 같은 속성
+            target := path.Join("/", header.Linkname)
+            if meta, ok := added[target]; ok {
+                added[name] = meta
+            } else if meta, ok := manifest[target]; ok {
+                added[name] = meta
+            }
+        default:
+            added[name] = metaFromHeader(header)
+        }
+    }
+
+    for _, dir := range opaque {
+        removeTree(manifest, dir, false)
+    }
+    for _, name := range removed {
+        removeTree(manifest, name, true)
+    }
+    for name, meta := range added {
+        manifest[name] = meta
+    }
+    return nil
+}
+
+// name 아래의 항목을 모두 제거 (self가 true면 name 자신도)
+func removeTree(manifest map[string]fileMeta, name string, self bool) {
+    if self {
+        delete(manifest, name)
+    }
+    prefix := strings.TrimSuffix(name, "/") + "/"
+    for p := range manifest {
+        if strings.HasPrefix(p, prefix) {
+            delete(manifest, p)
+        }
+    }
+}
+
+// 컨테이너가 만들어진 이미지의 파일 목록, 이미지 기록이 없으면 빈 목록 (모든 파일이 추가된 것으로 봄)
+func containerBaseManifest(containerName string) (map[string]fileMeta, *imageRecord, error) {
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return nil, nil, err
+    }
+    if state.Image == "" {
+        return map[string]fileMeta{}, nil, nil
+    }
+    img, err := loadImageRecord(state.Image)
+    if err != nil {
+        return nil, nil, err
+    }
+    manifest, err := imageManifest(img)
+    if err != nil {
+        return nil, nil, err
+    }
+    return manifest, img, nil
+}
+
+// 컨테이너 루트와 기준 파일 목록을 비교 (삭제는 가장 위의 경로만 보고)
+func computeChanges(root string, base map[string]fileMeta) ([]fileChange, error) {
+    var changes []fileChange
+    seen := map[string]bool{}
+
+    err := filepath.WalkDir(root, func(fullPath string, entry fs.DirEntry, err error) error {
+        if err != nil {
+            return err
+        }
+        if fullPath == root {
+            return nil
+        }
+        name := "/" + filepath.ToSlash(strings.TrimPrefix(fullPath, root+"/"))
+
+        info, err := entry.Info()
+        if err != nil {
+            return err
+        }
+        seen[name] = true
+        if meta, ok := base[name]; !ok {
+            changes = append(changes, fileChange{Kind: changeAdded, Path: name})
+        } else if metaFromFile(fullPath, info).differs(meta) {
+            changes = append(changes, fileChange{Kind: changeModified, Path: name})
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, fmt.Errorf("failed to walk container filesystem: %v", err)
+    }
+
+    deleted := map[string]bool{}
+    for name := range base {
+        if !seen[name] {
+            deleted[name] = true
+        }
+    }
+    for name := range deleted {
+        // 상위 디렉토리도 삭제되었으면 그 디렉토리의 삭제로 충분하므로 생략
+        if deleted[path.Dir(name)] {
+            continue
+        }
+        changes = append(changes, fileChange{Kind: changeDeleted, Path: name})
+    }
+
+    sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+    return changes, nil
+}
+
+// 변경 목록으로 레이어 tar 작성: 추가/변경은 파일 그대로, 삭제는 whiteout 항목
+func writeLayerTar(w io.Writer, root string, changes []fileChange) error {
+    tw := tar.NewWriter(w)
+    written := map[string]bool{}
+
+    // 변경된 파일의 상위 디렉토리도 속성을 보존하도록 먼저 기록
+    var writeParents func(name string) error
+    writeParents = func(name string) error {
+        dir := path.Dir(name)
+        if dir == "/" || written[dir] {
+            return nil
+        }
+        if err := writeParents(dir); err != nil {
+            return err
+        }
+        written[dir] = true
+        return writeTarEntry(tw, root, dir)
+    }
+
+    for _, change := range changes {
+        if err := writeParents(change.Path); err != nil {
+            return err
+        }
+        if change.Kind == changeDeleted {
+            dir, base := path.Split(change.Path)
+            header := &tar.Header{
+                Name:     strings.TrimPrefix(path.Join(dir, whiteoutPrefix+base), "/"),
+                Typeflag: tar.TypeReg,
+                Mode:     0600,
+                ModTime:  time.Unix(0, 0),
+                Format:   tar.FormatPAX,
+            }
+            if err := tw.WriteHeader(header); err != nil {
+                return err
+            }
+            continue
+        }
+        if written[change.Path] {
+            continue
+        }
+        written[change.Path] = true
+        if err := writeTarEntry(tw, root, change.Path); err != nil {
+            return err
+        }
+    }
+    return tw.Close()
+}
+
+// 루트 기준 경로 name의 파일을 tar 항목으로 기록
+func writeTarEntry(tw *tar.Writer, root, name string) error {
+    return writeTarFile(tw, filepath.Join(root, filepath.FromSlash(name)), strings.TrimPrefix(name, "/"))
+}
+
+// fullPath의 파일을 tar 항목 name으로 기록 (심볼릭 링크는 따라가지 않고 링크 자체를 기록)
+func writeTarFile(tw *tar.Writer, fullPath, name string) error {
+    info, err := os.Lstat(fullPath)
+    if err != nil {
+        return fmt.Errorf("failed to stat %s: %v", name, err)
+    }
+    header, err := tarHeaderFor(fullPath, info)
+    if err != nil {
+        return fmt.Errorf("failed to create tar header for %s: %v", name, err)
+    }
+    if header == nil {
+        return nil // 소켓 등 tar에 담을 수 없는 파일
+    }
+    header.Name = name
+    if info.IsDir() {
+        header.Name += "/"
+    }
+    if err := tw.WriteHeader(header); err != nil {
+        return err
+    }
+    if !info.Mode().IsRegular() {
+        return nil
+    }
+
+    file, err := os.OpenFile(fullPath, os.O_RDONLY|syscall.O_NOFOLLOW, 0)
+    if err != nil {
+        return fmt.Errorf("failed to open %s: %v", name, err)
+    }
+    defer file.Close()
+    if _, err := io.CopyN(tw, file, header.Size); err != nil {
+        return fmt.Errorf("failed to archive %s: %v", name, err)
+    }
+    return nil
+}
+
+func tarHeaderFor(fullPath string, info fs.FileInfo) (*tar.Header, error) {
+    if info.Mode()&os.ModeSocket != 0 {
+        return nil, nil
+    }
+    var link string
+    if info.Mode()&os.ModeSymlink != 0 {
+        var err error
+        if link, err = os.Readlink(fullPath); err != nil {
+            return nil, err
+        }
+    }
+    header, err := tar.FileInfoHeader(info, link)
+    if err != nil {
+        return nil, err
+    }
+    // 사용자 이름은 호스트 기준이라 의미가 없으므로 숫자 ID만 기록
+    header.Uname, header.Gname = "", ""
+    header.Format = tar.FormatPAX
+    header.AccessTime, header.ChangeTime = time.Time{}, time.Time{}
+    return header, nil
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+    "archive/tar"
+    "bytes"
+    "io"
+    "os"
+    "path/filepath"
+    "reflect"
+    "sort"
+    "testing"
+)
+
+// 컨테이너 루트의 현재 파일로 기준 목록을 만들고, 이미지에만 있던 경로를 더함
+func baseFromRoot(t *testing.T, root string, removed map[string]os.FileMode) map[string]fileMeta {
+    t.Helper()
+    base := map[string]fileMeta{}
+    err := filepath.Walk(root, func(fullPath string, info os.FileInfo, err error) error {
+        if err != nil || fullPath == root {
+            return err
+        }
+        base["/"+filepath.ToSlash(fullPath[len(root)+1:])] = metaFromFile(fullPath, info)
+        return nil
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    for name, mode := range removed {
+        base[name] = fileMeta{Mode: mode}
+    }
+    return base
+}
+
+func TestComputeChangesDeletions(t *testing.T) {
+    tests := []struct {
+        name    string
+        files   []string // 컨테이너 루트에 남아 있는 파일
+        removed map[string]os.FileMode
+        want    []fileChange
+    }{
+        {
+            name:    "deleted directory sorts between sibling and child",
+            files:   []string{"keep"},
+            removed: map[string]os.FileMode{"/a": os.ModeDir | 0755, "/a-b": 0644, "/a/c": 0644},
+            want:    []fileChange{{changeDeleted, "/a"}, {changeDeleted, "/a-b"}},
+        },
+        {
+            name:    "nested deletions collapse to the topmost directory",
+            files:   []string{"a-b"},
+            removed: map[string]os.FileMode{"/a": os.ModeDir | 0755, "/a/c": os.ModeDir | 0755, "/a/c/d": 0644, "/a/e": 0644},
+            want:    []fileChange{{changeDeleted, "/a"}},
+        },
+        {
+            name:    "file deleted from a remaining directory",
+            files:   []string{"a/keep"},
+            removed: map[string]os.FileMode{"/a/c": 0644},
+            want:    []fileChange{{changeDeleted, "/a/c"}},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            root := t.TempDir()
+            for _, file := range tt.files {
+                fullPath := filepath.Join(root, file)
+                if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+                    t.Fatal(err)
+                }
+                if err := os.WriteFile(fullPath, []byte(file), 0644); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            base := baseFromRoot(t, root, tt.removed)
+
+            changes, err := computeChanges(root, base)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(changes, tt.want) {
+                t.Fatalf("computeChanges() = %v, want %v", changes, tt.want)
+            }
+
+            // 삭제된 디렉토리 아래를 따로 보고하면 없는 상위 디렉토리를 기록하려다 실패함
+            var buf bytes.Buffer
+            if err := writeLayerTar(&buf, root, changes); err != nil {
+                t.Fatalf("writeLayerTar() error = %v", err)
+            }
+            var names []string
+            tr := tar.NewReader(&buf)
+            for {
+                header, err := tr.Next()
+                if err == io.EOF {
+                    break
+                }
+                if err != nil {
+                    t.Fatal(err)
+                }
+                names = append(names, header.Name)
+            }
+            if len(names) < len(tt.want) {
+                t.Fatalf("layer entries = %v, want at least %d whiteouts", names, len(tt.want))
+            }
+        })
+    }
+}
+
+func TestComputeChangesAddedAndModified(t *testing.T) {
+    root := t.TempDir()
+    for _, dir := range []string{"etc", "usr/bin"} {
+        if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+            t.Fatal(err)
+        }
+    }
+    for _, file := range []string{"etc/hosts", "etc/passwd", "usr/bin/sh"} {
+        if err := os.WriteFile(filepath.Join(root, file), []byte(file), 0644); err != nil {
+            t.Fatal(err)
+        }
+    }
+    base := baseFromRoot(t, root, nil)
+
+    // 내용 변경, 권한 변경, 새 파일, 새 링크 (상위 디렉토리의 수정 시각도 바뀜)
+    if err := os.WriteFile(filepath.Join(root, "etc/hosts"), []byte("changed contents"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Chmod(filepath.Join(root, "usr/bin/sh"), 0755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(filepath.Join(root, "etc/new"), nil, 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Symlink("/etc/hosts", filepath.Join(root, "hosts")); err != nil {
+        t.Fatal(err)
+    }
+    etc := base["/etc"]
+    etc.ModTime--
+    base["/etc"] = etc
+
+    changes, err := computeChanges(root, base)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []fileChange{
+        {changeModified, "/etc"},
+        {changeModified, "/etc/hosts"},
+        {changeAdded, "/etc/new"},
+        {changeAdded, "/hosts"},
+        {changeModified, "/usr/bin/sh"},
+    }
+    if !reflect.DeepEqual(changes, want) {
+        t.Fatalf("computeChanges() = %v, want %v", changes, want)
+    }
+}
+
+func TestApplyLayerManifest(t *testing.T) {
+    writeLayer := func(headers []tar.Header) string {
+        t.Helper()
+        path := filepath.Join(t.TempDir(), "layer.tar")
+        file, err := os.Create(path)
+        if err != nil {
+            t.Fatal(err)
+        }
+        defer file.Close()
+        tw := tar.NewWriter(file)
+        for i := range headers {
+            if err := tw.WriteHeader(&headers[i]); err != nil {
+                t.Fatal(err)
+            }
+            tw.Write(bytes.Repeat([]byte("x"), int(headers[i].Size)))
+        }
+        if err := tw.Close(); err != nil {
+            t.Fatal(err)
+        }
+        return path
+    }
+
+    manifest := map[string]fileMeta{}
+    base := writeLayer([]tar.Header{
+        {Name: "./", Typeflag: tar.TypeDir, Mode: 0755},
+        {Name: "a/", Typeflag: tar.TypeDir, Mode: 0755},
+        {Name: "a/x", Typeflag: tar.TypeReg, Mode: 0644, Size: 1},
+        {Name: "b/", Typeflag: tar.TypeDir, Mode: 0755},
+        {Name: "b/y", Typeflag: tar.TypeReg, Mode: 0644, Size: 1},
+        {Name: "c", Typeflag: tar.TypeReg, Mode: 0600, Size: 3},
+        {Name: "c-link", Typeflag: tar.TypeLink, Linkname: "c"},
+    })
+    // 같은 레이어의 추가 항목은 whiteout 순서와 상관없이 남아야 함
+    upper := writeLayer([]tar.Header{
+        {Name: "a/new", Typeflag: tar.TypeReg, Mode: 0644, Size: 2},
+        {Name: "a/.wh..wh..opq", Typeflag: tar.TypeReg},
+        {Name: ".wh.b", Typeflag: tar.TypeReg},
+        {Name: "c", Typeflag: tar.TypeReg, Mode: 0600, Size: 5},
+    })
+    for _, layer := range []string{base, upper} {
+        if err := applyLayerManifest(manifest, layer); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    var names []string
+    for name := range manifest {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    if want := []string{"/a", "/a/new", "/c", "/c-link"}; !reflect.DeepEqual(names, want) {
+        t.Fatalf("manifest = %v, want %v", names, want)
+    }
+    if manifest["/c"].Size != 5 || manifest["/c-link"].Size != 3 {
+        t.Fatalf("sizes of /c and /c-link = %d, %d, want 5, 3", manifest["/c"].Size, manifest["/c-link"].Size)
+    }
+}
@@ -0,0 +1,402 @@
+package cmd
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+const (
+    defaultLogMaxSize  = 10 * 1024 * 1024 // 로그 파일 하나의 최대 크기 (10MB)
+    defaultLogMaxFiles = 3                // 보관할 로그 파일 개수 (현재 파일 포함)
+)
+
+// logEntry : 로그 파일에 한 줄씩 기록되는 JSON 레코드
+type logEntry struct {
+    Log    string    `json:"log"`
+    Stream string    `json:"stream"`
+    Time   time.Time `json:"time"`
+}
+
+var (
+    logsFollow     bool
+    logsSince      string
+    logsTail       int
+    logsTimestamps bool
+)
+
+var logsCmd = &cobra.Command{
+    Use:   "logs [container]",
+    Short: "Fetch the logs of a container",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+
+        var since time.Time
+        if logsSince != "" {
+            t, err := parseSince(logsSince)
+            if err != nil {
+                return err
+            }
+            since = t
+        }
+
+        return showContainerLogs(containerName, since, logsTail, logsFollow, logsTimestamps)
+    },
+}
+
+func init() {
+    logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Follow log output")
+    logsCmd.Flags().StringVar(&logsSince, "since", "", "Show logs since timestamp (e.g. 2024-05-01T10:00:00Z) or relative (e.g. 10m)")
+    logsCmd.Flags().IntVar(&logsTail, "tail", -1, "Number of lines to show from the end of the logs")
+    logsCmd.Flags().BoolVarP(&logsTimestamps, "timestamps", "t", false, "Show timestamps")
+    rootCmd.AddCommand(logsCmd)
+}
+
+// 컨테이너 메타데이터(상태, 설정, 로그 등)를 보관하는 디렉토리
+// 컨테이너 루트 밖에 두어 컨테이너 안의 프로세스가 읽거나 고칠 수 없게 함
+func containerMetaDir(containerName string) string {
+    return filepath.Join("/CarteDaemon/meta", containerName)
+}
+
+// 컨테이너 로그 파일 경로
+func containerLogPath(containerName string) string {
+    return filepath.Join(containerMetaDir(containerName), "container.log")
+}
+
+// containerLog : stdout/stderr 스트림이 함께 사용하는 로그 파일 (크기 기준 로테이션)
+type containerLog struct {
+    mu       sync.Mutex
+    path     string
+    file     *os.File
+    size     int64
+    maxSize  int64
+    maxFiles int
+}
+
+func openContainerLog(path string, maxSize int64, maxFiles int) (*containerLog, error) {
+    if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+        return nil, fmt.Errorf("failed to create log directory: %v", err)
+    }
+
+    file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open log file: %v", err)
+    }
+    info, err := file.Stat()
+    if err != nil {
+        file.Close()
+        return nil, fmt.Errorf("failed to stat log file: %v", err)
+    }
+
+    if maxFiles < 1 {
+        maxFiles = 1
+    }
+
+    return &containerLog{path: path, file: file, size: info.Size(), maxSize: maxSize, maxFiles: maxFiles}, nil
+}
+
+// 로그 레코드 한 줄 기록
+func (l *containerLog) writeEntry(entry logEntry) error {
+    data, err := json.Marshal(entry)
+    if err != nil {
+        return err
+    }
+    data = append(data, '\n')
+
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    if l.maxSize > 0 && l.size > 0 && l.size+int64(len(data)) > l.maxSize {
+        if err := l.rotate(); err != nil {
+            return err
+        }
+    }
+
+    n, err := l.file.Write(data)
+    l.size += int64(n)
+    return err
+}
+
+// container.log -> container.log.1 -> container.log.2 ... 순서로 밀어내고 새 파일 생성
+func (l *containerLog) rotate() error {
+    if err := l.file.Close(); err != nil {
+        return fmt.Errorf("failed to close log file: %v", err)
+    }
+
+    if l.maxFiles > 1 {
+        for i := l.maxFiles - 1; i > 0; i-- {
+            src := l.path
+            if i > 1 {
+                src = fmt.Sprintf("%s.%d", l.path, i-1)
+            }
+            if err := os.Rename(src, fmt.Sprintf("%s.%d", l.path, i)); err != nil && !os.IsNotExist(err) {
+                return fmt.Errorf("failed to rotate log file: %v", err)
+            }
+        }
+    }
+
+    file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+    if err != nil {
+        return fmt.Errorf("failed to reopen log file: %v", err)
+    }
+    l.file = file
+    l.size = 0
+    return nil
+}
+
+func (l *containerLog) Close() error {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    return l.file.Close()
+}
+
+// streamWriter : 한 스트림(stdout 또는 stderr)의 출력을 줄 단위로 잘라 로그 파일에 기록
+type streamWriter struct {
+    log    *containerLog
+    stream string
+    buf    []byte
+}
+
+func (l *containerLog) stream(name string) *streamWriter {
+    return &streamWriter{log: l, stream: name}
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+    w.buf = append(w.buf, p...)
+    for {
+        i := bytes.IndexByte(w.buf, '\n')
+        if i < 0 {
+            break
+        }
+        line := string(w.buf[:i+1])
+        w.buf = w.buf[i+1:]
+        if err := w.log.writeEntry(logEntry{Log: line, Stream: w.stream, Time: time.Now().UTC()}); err != nil {
+            return 0, err
+        }
+    }
+    return len(p), nil
+}
+
+// 줄바꿈 없이 남아 있는 출력 기록
+func (w *streamWriter) Flush() error {
+    if len(w.buf) == 0 {
+        return nil
+    }
+    line := string(w.buf)
+    w.buf = nil
+    return w.log.writeEntry(logEntry{Log: line, Stream: w.stream, Time: time.Now().UTC()})
+}
+
+// --since 값 해석 (RFC3339 시각, 유닉스 시간 또는 10m 같은 상대 시간)
+func parseSince(value string) (time.Time, error) {
+    if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+        return t, nil
+    }
+    if d, err := time.ParseDuration(value); err == nil {
+        return time.Now().Add(-d), nil
+    }
+    if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+        return time.Unix(sec, 0), nil
+    }
+    return time.Time{}, fmt.Errorf("invalid --since value %q", value)
+}
+
+// 10m, 512k, 1g 같은 크기 표기를 바이트 수로 변환
+func parseSize(value string) (int64, error) {
+    value = strings.ToLower(strings.TrimSpace(value))
+    units := map[string]int64{"b": 1, "k": 1024, "m": 1024 * 1024, "g": 1024 * 1024 * 1024}
+
+    multiplier := int64(1)
+    value = strings.TrimSuffix(value, "b")
+    if value != "" {
+        if unit, ok := units[value[len(value)-1:]]; ok {
+            multiplier = unit
+            value = value[:len(value)-1]
+        }
+    }
+
+    n, err := strconv.ParseInt(value, 10, 64)
+    if err != nil || n < 0 {
+        return 0, fmt.Errorf("invalid size %q", value)
+    }
+    return n * multiplier, nil
+}
+
+// 로테이션된 파일을 포함해 오래된 순서로 로그 파일 목록 반환
+func containerLogFiles(containerName string) []string {
+    logPath := containerLogPath(containerName)
+    matches, _ := filepath.Glob(logPath + ".*")
+
+    rotated := map[int]string{}
+    var indexes []int
+    for _, match := range matches {
+        n, err := strconv.Atoi(strings.TrimPrefix(match, logPath+"."))
+        if err != nil {
+            continue
+        }
+        rotated[n] = match
+        indexes = append(indexes, n)
+    }
+    sort.Sort(sort.Reverse(sort.IntSlice(indexes)))
+
+    var files []string
+    for _, n := range indexes {
+        files = append(files, rotated[n])
+    }
+    return append(files, logPath)
+}
+
+func printLogEntry(entry logEntry, timestamps bool) {
+    out := os.Stdout
+    if entry.Stream == "stderr" {
+        out = os.Stderr
+    }
+    if timestamps {
+        fmt.Fprintf(out, "%s %s", entry.Time.Format(time.RFC3339Nano), entry.Log)
+        return
+    }
+    fmt.Fprint(out, entry.Log)
+}
+
+// 로그 파일에서 레코드를 읽어 조건에 맞는 것만 반환
+func readLogEntries(r io.Reader, since time.Time) ([]logEntry, error) {
+    var entries []logEntry
+    scanner := bufio.NewScanner(r)
+    scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+    for scanner.Scan() {
+        var entry logEntry
+        if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+            continue // 손상된 줄은 건너뜀
+        }
+        if !since.IsZero() && entry.Time.Before(since) {
+            continue
+        }
+        entries = append(entries, entry)
+    }
+    return entries, scanner.Err()
+}
+
+func showContainerLogs(containerName string, since time.Time, tail int, follow, timestamps bool) error {
+    if _, err := os.Stat(filepath.Join("/CarteDaemon/container", containerName)); os.IsNotExist(err) {
+        return fmt.Errorf("container %s does not exist", containerName)
+    }
+
+    var entries []logEntry
+    for _, path := range containerLogFiles(containerName) {
+        file, err := os.Open(path)
+        if os.IsNotExist(err) {
+            continue
+        }
+        if err != nil {
+            return fmt.Errorf("failed to open log file: %v", err)
+        }
+        fileEntries, err := readLogEntries(file, since)
+        file.Close()
+        if err != nil {
+            return fmt.Errorf("failed to read log file: %v", err)
+        }
+        entries = append(entries, fileEntries...)
+    }
+
+    if tail >= 0 && len(entries) > tail {
+        entries = entries[len(entries)-tail:]
+    }
+    for _, entry := range entries {
+        printLogEntry(entry, timestamps)
+    }
+
+    if !follow {
+        return nil
+    }
+    return followContainerLog(containerName, since, timestamps)
+}
+
+// 로그 파일 끝에서부터 새로 기록되는 내용을 계속 출력 (-f)
+func followContainerLog(containerName string, since time.Time, timestamps bool) error {
+    logPath := containerLogPath(containerName)
+
+    file, err := os.Open(logPath)
+    if err != nil && !os.IsNotExist(err) {
+        return fmt.Errorf("failed to open log file: %v", err)
+    }
+    var offset int64
+    if file != nil {
+        if offset, err = file.Seek(0, io.SeekEnd); err != nil {
+            file.Close()
+            return fmt.Errorf("failed to seek log file: %v", err)
+        }
+    }
+
+    var pending []byte
+    for {
+        if file == nil {
+            if file, err = os.Open(logPath); err != nil && !os.IsNotExist(err) {
+                return fmt.Errorf("failed to open log file: %v", err)
+            }
+            offset = 0
+        }
+
+        readBytes := 0
+        if file != nil {
+            chunk, err := io.ReadAll(file)
+            if err != nil {
+                file.Close()
+                return fmt.Errorf("failed to read log file: %v", err)
+            }
+            readBytes = len(chunk)
+            offset += int64(len(chunk))
+            pending = append(pending, chunk...)
+
+            for {
+                i := bytes.IndexByte(pending, '\n')
+                if i < 0 {
+                    break
+                }
+                var entry logEntry
+                if err := json.Unmarshal(pending[:i], &entry); err == nil {
+                    if since.IsZero() || !entry.Time.Before(since) {
+                        printLogEntry(entry, timestamps)
+                    }
+                }
+                pending = pending[i+1:]
+            }
+
+            // 로테이션으로 파일이 교체되었으면 새 파일을 처음부터 다시 읽음
+            if info, err := os.Stat(logPath); err == nil {
+                current, _ := file.Stat()
+                if !os.SameFile(info, current) || info.Size() < offset {
+                    file.Close()
+                    file = nil
+                    pending = nil
+                    continue
+                }
+            }
+        }
+
+        // 더 읽을 내용이 없고 컨테이너가 종료되었으면 끝냄
+        if readBytes == 0 {
+            if running, err := isContainerRunning(containerName); err == nil && !running {
+                if file != nil {
+                    file.Close()
+                }
+                return nil
+            }
+        }
+        time.Sleep(250 * time.Millisecond)
+    }
+}
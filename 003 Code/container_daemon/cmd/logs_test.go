@@ -0,0 +1,193 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestParseSize(t *testing.T) {
+    tests := []struct {
+        value   string
+        want    int64
+        wantErr bool
+    }{
+        {value: "10", want: 10},
+        {value: "0", want: 0},
+        {value: "10b", want: 10},
+        {value: "1k", want: 1024},
+        {value: "1kb", want: 1024},
+        {value: "512K", want: 512 * 1024},
+        {value: "5m", want: 5 * 1024 * 1024},
+        {value: "5MB", want: 5 * 1024 * 1024},
+        {value: "2g", want: 2 * 1024 * 1024 * 1024},
+        {value: " 3m ", want: 3 * 1024 * 1024},
+        {value: "", wantErr: true},
+        {value: "k", wantErr: true},
+        {value: "-1", wantErr: true},
+        {value: "-1m", wantErr: true},
+        {value: "1.5m", wantErr: true},
+        {value: "10t", wantErr: true},
+        {value: "ten", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.value, func(t *testing.T) {
+            got, err := parseSize(tt.value)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parseSize(%q) = %d, want error", tt.value, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatalf("parseSize(%q) error = %v", tt.value, err)
+            }
+            if got != tt.want {
+                t.Fatalf("parseSize(%q) = %d, want %d", tt.value, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseSince(t *testing.T) {
+    now := time.Now()
+    tests := []struct {
+        value   string
+        want    time.Time
+        slack   time.Duration // 상대 시간은 현재 시각 기준이므로 오차 허용
+        wantErr bool
+    }{
+        {value: "2024-05-01T10:00:00Z", want: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+        {value: "2024-05-01T10:00:00.5+09:00", want: time.Date(2024, 5, 1, 1, 0, 0, 500000000, time.UTC)},
+        {value: "1714557600", want: time.Unix(1714557600, 0)},
+        {value: "10m", want: now.Add(-10 * time.Minute), slack: time.Minute},
+        {value: "1h30m", want: now.Add(-90 * time.Minute), slack: time.Minute},
+        {value: "yesterday", wantErr: true},
+        {value: "2024-05-01", wantErr: true},
+        {value: "", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.value, func(t *testing.T) {
+            got, err := parseSince(tt.value)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parseSince(%q) = %v, want error", tt.value, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if diff := got.Sub(tt.want); diff < -tt.slack || diff > tt.slack {
+                t.Fatalf("parseSince(%q) = %v, want %v", tt.value, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestReadLogEntries(t *testing.T) {
+    base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+    input := strings.Join([]string{
+        fmt.Sprintf(`{"log":"one\n","stream":"stdout","time":%q}`, base.Format(time.RFC3339Nano)),
+        `not json`,
+        fmt.Sprintf(`{"log":"two\n","stream":"stderr","time":%q}`, base.Add(time.Minute).Format(time.RFC3339Nano)),
+        fmt.Sprintf(`{"log":"three","stream":"stdout","time":%q}`, base.Add(2*time.Minute).Format(time.RFC3339Nano)),
+    }, "\n")
+
+    tests := []struct {
+        name  string
+        since time.Time
+        want  []string
+    }{
+        {name: "all", want: []string{"one\n", "two\n", "three"}},
+        {name: "since is inclusive", since: base.Add(time.Minute), want: []string{"two\n", "three"}},
+        {name: "since after last", since: base.Add(time.Hour)},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            entries, err := readLogEntries(strings.NewReader(input), tt.since)
+            if err != nil {
+                t.Fatal(err)
+            }
+            var got []string
+            for _, entry := range entries {
+                got = append(got, entry.Log)
+            }
+            if !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("readLogEntries() = %q, want %q", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestContainerLogRotation(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "container.log")
+    log, err := openContainerLog(path, 200, 3)
+    if err != nil {
+        t.Fatal(err)
+    }
+    stdout := log.stream("stdout")
+    // 줄 단위로 나뉘고, 남은 조각은 Flush에서 기록
+    for i := 0; i < 10; i++ {
+        if _, err := fmt.Fprintf(stdout, "line %d with some padding\n", i); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if _, err := stdout.Write([]byte("la")); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := stdout.Write([]byte("st")); err != nil {
+        t.Fatal(err)
+    }
+    if err := stdout.Flush(); err != nil {
+        t.Fatal(err)
+    }
+    if err := log.Close(); err != nil {
+        t.Fatal(err)
+    }
+
+    // maxFiles=3: container.log, .1, .2 만 남음
+    var lines []string
+    for _, name := range []string{path + ".2", path + ".1", path} {
+        file, err := os.Open(name)
+        if err != nil {
+            t.Fatal(err)
+        }
+        info, _ := file.Stat()
+        if info.Size() > 200 {
+            t.Errorf("%s is %d bytes, want at most 200", name, info.Size())
+        }
+        entries, err := readLogEntries(file, time.Time{})
+        file.Close()
+        if err != nil {
+            t.Fatal(err)
+        }
+        for _, entry := range entries {
+            if entry.Stream != "stdout" {
+                t.Errorf("entry stream = %q, want stdout", entry.Stream)
+            }
+            lines = append(lines, entry.Log)
+        }
+    }
+    if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+        t.Fatalf("%s.3 exists, want at most 3 files", path)
+    }
+    if len(lines) == 0 || lines[len(lines)-1] != "last" {
+        t.Fatalf("newest entries = %q, want the flushed partial line last", lines)
+    }
+    for i := 1; i < len(lines)-1; i++ {
+        var prev, cur int
+        fmt.Sscanf(lines[i-1], "line %d", &prev)
+        fmt.Sscanf(lines[i], "line %d", &cur)
+        if cur != prev+1 {
+            t.Fatalf("entries out of order across rotated files: %q", lines)
+        }
+    }
+}
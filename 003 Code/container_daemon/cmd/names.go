@@ -0,0 +1,150 @@
+package cmd
+
+import (
+    "fmt"
+    "math/rand"
+    "os"
+    "regexp"
+    "sort"
+    "strings"
+)
+
+var (
+    containerIDPattern   = regexp.MustCompile(`^[0-9a-f]{64}$`)
+    containerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+)
+
+// 자동 생성 이름에 쓰는 단어 (형용사_명사)
+var (
+    nameAdjectives = []string{
+        "agile", "bold", "brave", "bright", "calm", "clever", "cool", "crisp", "eager", "fancy",
+        "gentle", "happy", "jolly", "keen", "kind", "lively", "lucky", "mellow", "merry", "nimble",
+        "noble", "proud", "quick", "quiet", "rapid", "shiny", "silent", "sleepy", "smart", "snowy",
+        "sunny", "swift", "tidy", "vivid", "warm", "wise", "witty", "young", "zesty", "zen",
+    }
+    nameNouns = []string{
+        "badger", "beacon", "canyon", "cedar", "comet", "coral", "crane", "dolphin", "falcon", "fern",
+        "fox", "glacier", "harbor", "heron", "island", "lantern", "lynx", "maple", "meadow", "meteor",
+        "nebula", "otter", "owl", "panda", "pebble", "pine", "quartz", "raven", "river", "rocket",
+        "sparrow", "summit", "tiger", "tundra", "valley", "walrus", "willow", "wolf", "yak", "zebra",
+    }
+)
+
+// 다른 컨테이너와 겹치지 않는 이름 생성
+func generateContainerName(exclude string) (string, error) {
+    used, err := usedContainerNames(exclude)
+    if err != nil {
+        return "", err
+    }
+    for i := 0; i < 100; i++ {
+        name := nameAdjectives[rand.Intn(len(nameAdjectives))] + "_" + nameNouns[rand.Intn(len(nameNouns))]
+        // 조합이 모두 쓰였을 때를 대비해 충돌이 계속되면 숫자를 붙임
+        if i >= 10 {
+            name = fmt.Sprintf("%s%d", name, rand.Intn(100))
+        }
+        if !used[name] {
+            return name, nil
+        }
+    }
+    return "", fmt.Errorf("failed to generate a unique container name")
+}
+
+// 사용 중인 컨테이너 이름 목록 (exclude 디렉토리는 제외)
+func usedContainerNames(exclude string) (map[string]bool, error) {
+    files, err := os.ReadDir("/CarteDaemon/container")
+    if err != nil {
+        return nil, fmt.Errorf("failed to read container directory: %v", err)
+    }
+    used := map[string]bool{}
+    for _, file := range files {
+        if !file.IsDir() || file.Name() == exclude {
+            continue
+        }
+        // 아직 상태 파일이 없는 컨테이너는 디렉토리 이름이 곧 이름
+        if state, err := readContainerState(file.Name()); err == nil {
+            used[state.Name] = true
+        } else {
+            used[file.Name()] = true
+        }
+    }
+    return used, nil
+}
+
+// 컨테이너 이름 변경 (start --name)
+func renameContainer(containerName, newName string) error {
+    if !containerNamePattern.MatchString(newName) {
+        return fmt.Errorf("invalid container name %q, only [a-zA-Z0-9][a-zA-Z0-9_.-] are allowed", newName)
+    }
+    if containerIDPattern.MatchString(newName) {
+        return fmt.Errorf("invalid container name %q, names must not look like container IDs", newName)
+    }
+    used, err := usedContainerNames(containerName)
+    if err != nil {
+        return err
+    }
+    if used[newName] {
+        return fmt.Errorf("container name %q is already in use", newName)
+    }
+    return updateContainerState(containerName, func(s *containerState) error {
+        s.Name = newName
+        return nil
+    })
+}
+
+// 이름, 전체 ID 또는 ID 접두사로 컨테이너를 찾아 디렉토리 이름을 반환
+// 우선순위는 전체 ID, 이름, 유일한 ID 접두사 순
+func resolveContainer(ref string) (string, error) {
+    if ref == "" {
+        return "", fmt.Errorf("container name or ID must not be empty")
+    }
+    files, err := os.ReadDir("/CarteDaemon/container")
+    if err != nil {
+        return "", fmt.Errorf("failed to read container directory: %v", err)
+    }
+
+    var byName, byPrefix []string
+    for _, file := range files {
+        if !file.IsDir() {
+            continue
+        }
+        state, err := loadContainerState(file.Name())
+        if err != nil {
+            continue
+        }
+        if state.ID == ref {
+            return file.Name(), nil
+        }
+        if state.Name == ref {
+            byName = append(byName, file.Name())
+        }
+        if strings.HasPrefix(state.ID, ref) {
+            byPrefix = append(byPrefix, file.Name())
+        }
+    }
+
+    if len(byName) == 1 {
+        return byName[0], nil
+    }
+    if len(byName) > 1 {
+        return "", fmt.Errorf("multiple containers are named %q", ref)
+    }
+    switch len(byPrefix) {
+    case 0:
+        return "", fmt.Errorf("no such container: %s", ref)
+    case 1:
+        return byPrefix[0], nil
+    }
+    return "", fmt.Errorf("container ID prefix %q is ambiguous, matches: %s", ref, describeContainers(byPrefix))
+}
+
+// 오류 메시지용 "이름 (ID)" 목록 (짧은 ID는 서로 같을 수 있으므로 전체 ID)
+func describeContainers(dirs []string) string {
+    var list []string
+    for _, dir := range dirs {
+        if state, err := readContainerState(dir); err == nil {
+            list = append(list, fmt.Sprintf("%s (%s)", state.Name, state.ID))
+        }
+    }
+    sort.Strings(list)
+    return strings.Join(list, ", ")
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+    "testing"
+)
+
+func TestNewContainerID(t *testing.T) {
+    id, err := newContainerID()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !containerIDPattern.MatchString(id) {
+        t.Fatalf("newContainerID() = %q, want 64 hex digits", id)
+    }
+    if other, _ := newContainerID(); other == id {
+        t.Fatalf("newContainerID() returned %q twice", id)
+    }
+}
+
+func TestContainerNamePattern(t *testing.T) {
+    tests := []struct {
+        name string
+        want bool
+    }{
+        {name: "web", want: true},
+        {name: "web_1.prod-2", want: true},
+        {name: "9lives", want: true},
+        {name: "", want: false},
+        {name: "_web", want: false},
+        {name: "-web", want: false},
+        {name: "web/1", want: false},
+        {name: "web 1", want: false},
+    }
+
+    for _, tt := range tests {
+        if got := containerNamePattern.MatchString(tt.name); got != tt.want {
+            t.Fatalf("containerNamePattern.MatchString(%q) = %v, want %v", tt.name, got, tt.want)
+        }
+    }
+}
+
+// 자동 생성 이름은 모두 --name 규칙을 만족하고 ID로 오인되지 않아야 함
+func TestGeneratedNameWords(t *testing.T) {
+    for _, adjective := range nameAdjectives {
+        for _, noun := range nameNouns {
+            name := adjective + "_" + noun + "99"
+            if !containerNamePattern.MatchString(name) || containerIDPattern.MatchString(name) {
+                t.Fatalf("generated name %q is not a valid container name", name)
+            }
+        }
+    }
+}
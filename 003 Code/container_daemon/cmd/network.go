@@ -0,0 +1,407 @@
+package cmd
+
+import (
+    "encoding/binary"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "sync"
+    "syscall"
+    "time"
+)
+
+// 컨테이너마다 서브넷 풀 안의 /24 서브넷을 하나씩 할당 (호스트 .1, 컨테이너 .2)
+// 흔한 LAN 대역과 겹치지 않도록 기본값은 10.88.0.0/16, CARTE_SUBNET_POOL 환경 변수로 변경
+const (
+    defaultSubnetPool = "10.88.0.0/16"
+    subnetPoolEnv     = "CARTE_SUBNET_POOL"
+)
+
+// portMapping : -p [hostIP:]hostPort:containerPort[/tcp|udp]
+type portMapping struct {
+    HostIP        string `json:"hostIP"`
+    HostPort      int    `json:"hostPort"`
+    ContainerPort int    `json:"containerPort"`
+    Protocol      string `json:"protocol"`
+}
+
+func (p portMapping) String() string {
+    return fmt.Sprintf("%s:%d->%d/%s", p.HostIP, p.HostPort, p.ContainerPort, p.Protocol)
+}
+
+// containerNetwork : 컨테이너에 할당된 네트워크 정보 (메타데이터 디렉토리의 network.json)
+type containerNetwork struct {
+    SubnetIndex int           `json:"subnetIndex"` // 서브넷 풀 안에서의 순번
+    HostIP      string        `json:"hostIP"`
+    ContainerIP string        `json:"containerIP"`
+    HostVeth    string        `json:"hostVeth"` // 호스트 쪽 veth 이름 (컨테이너 쪽은 eth0)
+    Netns       string        `json:"netns"`    // /run/netns 아래의 네임스페이스 링크 이름
+    Ports       []portMapping `json:"ports,omitempty"`
+}
+
+func containerNetworkPath(containerName string) string {
+    return filepath.Join(containerMetaDir(containerName), "network.json")
+}
+
+// 포트 공개 옵션 해석
+func parsePortMapping(spec string) (portMapping, error) {
+    mapping := portMapping{HostIP: "0.0.0.0", Protocol: "tcp"}
+
+    if i := strings.LastIndex(spec, "/"); i >= 0 {
+        mapping.Protocol = strings.ToLower(spec[i+1:])
+        spec = spec[:i]
+    }
+    if mapping.Protocol != "tcp" && mapping.Protocol != "udp" {
+        return mapping, fmt.Errorf("invalid protocol %q in port mapping", mapping.Protocol)
+    }
+
+    parts := strings.Split(spec, ":")
+    switch len(parts) {
+    case 2:
+    case 3:
+        if net.ParseIP(parts[0]) == nil {
+            return mapping, fmt.Errorf("invalid host IP %q in port mapping", parts[0])
+        }
+        mapping.HostIP = parts[0]
+        parts = parts[1:]
+    default:
+        return mapping, fmt.Errorf("invalid port mapping %q, expected [hostIP:]hostPort:containerPort[/tcp|udp]", spec)
+    }
+
+    hostPort, err := strconv.Atoi(parts[0])
+    if err != nil || hostPort < 1 || hostPort > 65535 {
+        return mapping, fmt.Errorf("invalid host port %q", parts[0])
+    }
+    containerPort, err := strconv.Atoi(parts[1])
+    if err != nil || containerPort < 1 || containerPort > 65535 {
+        return mapping, fmt.Errorf("invalid container port %q", parts[1])
+    }
+    mapping.HostPort = hostPort
+    mapping.ContainerPort = containerPort
+    return mapping, nil
+}
+
+// 같은 요청 안에서 호스트 포트가 겹치는지 확인
+func checkDuplicatePorts(ports []portMapping) error {
+    seen := map[string]bool{}
+    for _, port := range ports {
+        key := fmt.Sprintf("%d/%s", port.HostPort, port.Protocol)
+        if seen[key] {
+            return fmt.Errorf("host port %s is published more than once", key)
+        }
+        seen[key] = true
+    }
+    return nil
+}
+
+// 컨테이너 서브넷을 할당할 IPv4 풀 (/24 이상의 크기)
+func containerSubnetPool() (*net.IPNet, error) {
+    pool := defaultSubnetPool
+    if value := os.Getenv(subnetPoolEnv); value != "" {
+        pool = value
+    }
+    _, ipnet, err := net.ParseCIDR(pool)
+    if err != nil || ipnet.IP.To4() == nil {
+        return nil, fmt.Errorf("invalid subnet pool %q, expected an IPv4 CIDR such as %s", pool, defaultSubnetPool)
+    }
+    if ones, _ := ipnet.Mask.Size(); ones > 24 {
+        return nil, fmt.Errorf("subnet pool %s is smaller than a /24", pool)
+    }
+    return ipnet, nil
+}
+
+// 풀의 index번째 /24 서브넷에서 host번째 주소
+func subnetAddress(pool *net.IPNet, index, host int) string {
+    ip := make(net.IP, 4)
+    binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(pool.IP.To4())+uint32(index)<<8+uint32(host))
+    return ip.String()
+}
+
+// 주소가 속한 /24 서브넷
+func subnetOf(ip string) string {
+    parsed := net.ParseIP(ip)
+    if parsed == nil {
+        return ""
+    }
+    return parsed.Mask(net.CIDRMask(24, 32)).String()
+}
+
+// carte가 아닌 프로세스가 이미 쓰고 있는 호스트 포트인지 미리 확인 (프록시 시작 전에 실패하도록)
+func checkHostPortFree(port portMapping) error {
+    addr := net.JoinHostPort(port.HostIP, strconv.Itoa(port.HostPort))
+    var closer io.Closer
+    var err error
+    if port.Protocol == "udp" {
+        closer, err = net.ListenPacket("udp", addr)
+    } else {
+        closer, err = net.Listen("tcp", addr)
+    }
+    if err != nil {
+        return fmt.Errorf("host port %d/%s is not available: %v", port.HostPort, port.Protocol, err)
+    }
+    return closer.Close()
+}
+
+func loadContainerNetwork(containerName string) (*containerNetwork, error) {
+    data, err := os.ReadFile(containerNetworkPath(containerName))
+    if err != nil {
+        return nil, err
+    }
+    var network containerNetwork
+    if err := json.Unmarshal(data, &network); err != nil {
+        return nil, fmt.Errorf("failed to parse network config for container %s: %v", containerName, err)
+    }
+    return &network, nil
+}
+
+func saveContainerNetwork(containerName string, network *containerNetwork) error {
+    if err := os.MkdirAll(containerMetaDir(containerName), 0700); err != nil {
+        return fmt.Errorf("failed to create container meta directory: %v", err)
+    }
+    data, err := json.MarshalIndent(network, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(containerNetworkPath(containerName), data, 0644)
+}
+
+// 정리된 컨테이너의 서브넷과 포트 예약 해제
+func releaseContainerNetwork(containerName string) {
+    if err := os.Remove(containerNetworkPath(containerName)); err != nil && !os.IsNotExist(err) {
+        fmt.Printf("Warning: failed to release network of container %s: %v\n", containerName, err)
+    }
+}
+
+// 컨테이너가 veth로 호스트에 연결됨 (이벤트의 id/name은 컨테이너)
+func recordNetworkConnectEvent(containerName string, network *containerNetwork) {
+    event := lifecycleEvent{
+        Type:       "network",
+        Action:     "connect",
+        ID:         containerName,
+        Name:       containerName,
+        Attributes: map[string]string{"ip": network.ContainerIP, "hostIP": network.HostIP, "veth": network.HostVeth},
+    }
+    if state, err := readContainerState(containerName); err == nil {
+        event.ID, event.Name = state.ID, state.Name
+    }
+    recordEvent(event)
+}
+
+// 다른 컨테이너가 예약한(network.json이 있는) 서브넷과 겹치지 않는 서브넷을 골라 저장
+func allocateContainerNetwork(containerName string, ports []portMapping) (*containerNetwork, error) {
+    // 인터페이스 이름은 짧은 컨테이너 ID로 만듦 (IFNAMSIZ 제한으로 최대 15자)
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return nil, err
+    }
+    id := shortID(state.ID)
+
+    // 동시에 시작되는 컨테이너끼리 같은 서브넷을 받지 않도록 잠금
+    lockFile, err := os.OpenFile("/CarteDaemon/network.lock", os.O_CREATE|os.O_RDWR, 0600)
+    if err != nil {
+        return nil, fmt.Errorf("failed to open network lock: %v", err)
+    }
+    defer lockFile.Close()
+    if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+        return nil, fmt.Errorf("failed to lock network allocation: %v", err)
+    }
+    defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+
+    pool, err := containerSubnetPool()
+    if err != nil {
+        return nil, err
+    }
+
+    used := map[string]bool{}
+    files, err := os.ReadDir("/CarteDaemon/container")
+    if err != nil {
+        return nil, fmt.Errorf("failed to read container directory: %v", err)
+    }
+    for _, file := range files {
+        if !file.IsDir() || file.Name() == containerName {
+            continue
+        }
+        network, err := loadContainerNetwork(file.Name())
+        if err != nil {
+            continue
+        }
+        // network.json은 시작할 때 잠금 안에서 기록되고 종료 후 정리될 때 지워지므로,
+        // running이 기록되기 전(시작 중)이나 정리되지 않은 dead 컨테이너의 서브넷과 포트도 사용 중으로 봄
+        used[subnetOf(network.HostIP)] = true
+        for _, port := range network.Ports {
+            for _, requested := range ports {
+                if port.HostPort == requested.HostPort && port.Protocol == requested.Protocol {
+                    return nil, fmt.Errorf("host port %d/%s is already published by container %s", port.HostPort, port.Protocol, file.Name())
+                }
+            }
+        }
+    }
+
+    for _, port := range ports {
+        if err := checkHostPortFree(port); err != nil {
+            return nil, err
+        }
+    }
+
+    ones, _ := pool.Mask.Size()
+    for index := 0; index < 1<<(24-ones); index++ {
+        hostIP := subnetAddress(pool, index, 1)
+        if used[subnetOf(hostIP)] {
+            continue
+        }
+        network := &containerNetwork{
+            SubnetIndex: index,
+            HostIP:      hostIP,
+            ContainerIP: subnetAddress(pool, index, 2),
+            HostVeth:    "vh_" + id,
+            Netns:       "carte_" + id,
+            Ports:       ports,
+        }
+        if err := saveContainerNetwork(containerName, network); err != nil {
+            return nil, err
+        }
+        return network, nil
+    }
+    return nil, fmt.Errorf("no free container subnet left in %s", pool)
+}
+
+// portProxy : 호스트 포트로 들어온 연결을 컨테이너 IP로 전달하는 사용자 공간 프록시
+type portProxy struct {
+    closers []io.Closer
+    wg      sync.WaitGroup
+}
+
+// 공개 포트마다 리스너를 열고 전달을 시작
+func startPortProxies(containerIP string, ports []portMapping) (*portProxy, error) {
+    proxy := &portProxy{}
+    for _, port := range ports {
+        hostAddr := net.JoinHostPort(port.HostIP, strconv.Itoa(port.HostPort))
+        containerAddr := net.JoinHostPort(containerIP, strconv.Itoa(port.ContainerPort))
+
+        if port.Protocol == "udp" {
+            conn, err := net.ListenPacket("udp", hostAddr)
+            if err != nil {
+                proxy.Close()
+                return nil, fmt.Errorf("failed to listen on %s/udp: %v", hostAddr, err)
+            }
+            proxy.closers = append(proxy.closers, conn)
+            proxy.wg.Add(1)
+            go func() {
+                defer proxy.wg.Done()
+                proxyUDP(conn, containerAddr)
+            }()
+            continue
+        }
+
+        listener, err := net.Listen("tcp", hostAddr)
+        if err != nil {
+            proxy.Close()
+            return nil, fmt.Errorf("failed to listen on %s/tcp: %v", hostAddr, err)
+        }
+        proxy.closers = append(proxy.closers, listener)
+        proxy.wg.Add(1)
+        go func() {
+            defer proxy.wg.Done()
+            proxyTCP(listener, containerAddr)
+        }()
+    }
+    return proxy, nil
+}
+
+func (p *portProxy) Close() {
+    for _, closer := range p.closers {
+        closer.Close()
+    }
+    p.wg.Wait()
+}
+
+func proxyTCP(listener net.Listener, containerAddr string) {
+    for {
+        client, err := listener.Accept()
+        if err != nil {
+            return
+        }
+        go func() {
+            defer client.Close()
+            backend, err := net.DialTimeout("tcp", containerAddr, 5*time.Second)
+            if err != nil {
+                fmt.Printf("Warning: failed to connect to %s: %v\n", containerAddr, err)
+                return
+            }
+            defer backend.Close()
+
+            done := make(chan struct{}, 2)
+            pipe := func(dst, src net.Conn) {
+                io.Copy(dst, src)
+                // 한쪽이 끝나면 반대쪽에 쓰기 종료를 알림
+                if tcp, ok := dst.(*net.TCPConn); ok {
+                    tcp.CloseWrite()
+                }
+                done <- struct{}{}
+            }
+            go pipe(backend, client)
+            go pipe(client, backend)
+            <-done
+            <-done
+        }()
+    }
+}
+
+// UDP는 클라이언트 주소마다 컨테이너 쪽 소켓을 하나씩 만들어 응답을 돌려보냄
+func proxyUDP(conn net.PacketConn, containerAddr string) {
+    const idleTimeout = 60 * time.Second
+
+    var mu sync.Mutex
+    backends := map[string]net.Conn{}
+    defer func() {
+        mu.Lock()
+        for _, backend := range backends {
+            backend.Close()
+        }
+        mu.Unlock()
+    }()
+
+    buf := make([]byte, 65535)
+    for {
+        n, clientAddr, err := conn.ReadFrom(buf)
+        if err != nil {
+            return
+        }
+
+        mu.Lock()
+        backend, ok := backends[clientAddr.String()]
+        if !ok {
+            backend, err = net.Dial("udp", containerAddr)
+            if err != nil {
+                mu.Unlock()
+                fmt.Printf("Warning: failed to connect to %s: %v\n", containerAddr, err)
+                continue
+            }
+            backends[clientAddr.String()] = backend
+
+            go func(backend net.Conn, clientAddr net.Addr) {
+                reply := make([]byte, 65535)
+                for {
+                    backend.SetReadDeadline(time.Now().Add(idleTimeout))
+                    n, err := backend.Read(reply)
+                    if err != nil {
+                        break
+                    }
+                    conn.WriteTo(reply[:n], clientAddr)
+                }
+                mu.Lock()
+                delete(backends, clientAddr.String())
+                mu.Unlock()
+                backend.Close()
+            }(backend, clientAddr)
+        }
+        mu.Unlock()
+
+        backend.Write(buf[:n])
+    }
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+    "testing"
+)
+
+func TestParsePortMapping(t *testing.T) {
+    tests := []struct {
+        spec    string
+        want    portMapping
+        wantErr bool
+    }{
+        {spec: "8080:80", want: portMapping{HostIP: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}},
+        {spec: "127.0.0.1:8080:80", want: portMapping{HostIP: "127.0.0.1", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}},
+        {spec: "53:53/udp", want: portMapping{HostIP: "0.0.0.0", HostPort: 53, ContainerPort: 53, Protocol: "udp"}},
+        {spec: "53:53/UDP", want: portMapping{HostIP: "0.0.0.0", HostPort: 53, ContainerPort: 53, Protocol: "udp"}},
+        {spec: "10.0.0.1:1:65535/tcp", want: portMapping{HostIP: "10.0.0.1", HostPort: 1, ContainerPort: 65535, Protocol: "tcp"}},
+        {spec: "80", wantErr: true},
+        {spec: "1:2:3:4", wantErr: true},
+        {spec: "8080:80/sctp", wantErr: true},
+        {spec: "localhost:8080:80", wantErr: true},
+        {spec: "0:80", wantErr: true},
+        {spec: "65536:80", wantErr: true},
+        {spec: "8080:0", wantErr: true},
+        {spec: "http:80", wantErr: true},
+        {spec: "8080:", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.spec, func(t *testing.T) {
+            got, err := parsePortMapping(tt.spec)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parsePortMapping(%q) = %+v, want error", tt.spec, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatalf("parsePortMapping(%q) error = %v", tt.spec, err)
+            }
+            if got != tt.want {
+                t.Fatalf("parsePortMapping(%q) = %+v, want %+v", tt.spec, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestCheckDuplicatePorts(t *testing.T) {
+    tests := []struct {
+        name    string
+        specs   []string
+        wantErr bool
+    }{
+        {"distinct ports", []string{"8080:80", "8081:80"}, false},
+        {"same port different protocol", []string{"53:53/tcp", "53:53/udp"}, false},
+        {"same host port", []string{"8080:80", "8080:81"}, true},
+        // 호스트 IP가 달라도 프록시는 같은 포트를 쓰므로 중복으로 봄
+        {"same host port on another address", []string{"127.0.0.1:8080:80", "8080:80"}, true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var ports []portMapping
+            for _, spec := range tt.specs {
+                port, err := parsePortMapping(spec)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                ports = append(ports, port)
+            }
+            if err := checkDuplicatePorts(ports); (err != nil) != tt.wantErr {
+                t.Fatalf("checkDuplicatePorts(%v) error = %v, wantErr %v", tt.specs, err, tt.wantErr)
+            }
+        })
+    }
+}
+
+func TestContainerSubnetPool(t *testing.T) {
+    tests := []struct {
+        pool    string
+        index   int
+        host    int
+        want    string
+        wantErr bool
+    }{
+        {pool: "", index: 0, host: 1, want: "10.88.0.1"},
+        {pool: "", index: 3, host: 2, want: "10.88.3.2"},
+        {pool: "172.30.0.0/16", index: 255, host: 2, want: "172.30.255.2"},
+        {pool: "192.168.7.0/24", index: 0, host: 1, want: "192.168.7.1"},
+        // 풀 주소는 네트워크 주소로 정리됨
+        {pool: "10.1.2.3/16", index: 1, host: 1, want: "10.1.1.1"},
+        {pool: "10.0.0.0/25", wantErr: true},
+        {pool: "fd00::/64", wantErr: true},
+        {pool: "not-a-cidr", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.pool, func(t *testing.T) {
+            t.Setenv(subnetPoolEnv, tt.pool)
+            pool, err := containerSubnetPool()
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("containerSubnetPool() = %v, want error", pool)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            got := subnetAddress(pool, tt.index, tt.host)
+            if got != tt.want {
+                t.Fatalf("subnetAddress(%v, %d, %d) = %s, want %s", pool, tt.index, tt.host, got, tt.want)
+            }
+            if subnetOf(got) != subnetAddress(pool, tt.index, 0) {
+                t.Fatalf("subnetOf(%s) = %s, want %s", got, subnetOf(got), subnetAddress(pool, tt.index, 0))
+            }
+        })
+    }
+}
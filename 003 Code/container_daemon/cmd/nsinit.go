@@ -0,0 +1,260 @@
+package cmd
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "os/exec"
+    "runtime"
+    "syscall"
+
+    "github.com/spf13/cobra"
+    "golang.org/x/sys/unix"
+)
+
+// initConfig : nsinit이 컨테이너 프로세스로 바뀌기 직전에 적용할 설정 (부모가 fd 3 파이프로 전달)
+type initConfig struct {
+    Root          string            `json:"root,omitempty"`    // 컨테이너 루트의 호스트 경로
+    Pivot         bool              `json:"pivot,omitempty"`   // true면 루트를 구성하고 pivot_root, false면 chroot만
+    Hostname      string            `json:"hostname,omitempty"`
+    ReadonlyRoot  bool              `json:"readonlyRoot,omitempty"`
+    Volumes       []volumeMount     `json:"volumes,omitempty"`
+    Tmpfs         []tmpfsMount      `json:"tmpfs,omitempty"`
+    MaskedPaths   []string          `json:"maskedPaths,omitempty"`
+    ReadonlyPaths []string          `json:"readonlyPaths,omitempty"`
+    Path          string            `json:"path"`              // 루트 기준 실행 파일 경로
+    Args          []string          `json:"args"`
+    Env           []string          `json:"env"`
+    Cwd           string            `json:"cwd"`
+    Uid           uint32            `json:"uid"`
+    Gid           uint32            `json:"gid"`
+    Capabilities  []string          `json:"capabilities"`
+    Seccomp       []unix.SockFilter `json:"seccomp,omitempty"`
+    Console       bool              `json:"console,omitempty"` // 컨테이너 devpts에서 PTY를 할당해 consoleSocketFd로 전달
+    Init          bool              `json:"init,omitempty"`    // exec 대신 PID 1로 남아 명령을 자식으로 실행 (--init)
+
+    // OCI 번들 실행 (carte runtime create)
+    // 마운트와 장치를 번들 설정대로 구성하고, initSyncFd로 부모와 훅 실행/준비 완료를 주고받은 뒤
+    // execFifoFd의 fifo를 runtime start가 열 때까지 대기
+    Bundle             bool              `json:"bundle,omitempty"`
+    Mounts             []specMount       `json:"mounts,omitempty"`
+    Devices            []specDevice      `json:"devices,omitempty"`
+    Sysctl             map[string]string `json:"sysctl,omitempty"`
+    RootPropagation    uintptr           `json:"rootPropagation,omitempty"`
+    Namespaces         []specNamespace   `json:"namespaces,omitempty"` // 경로로 합류할 기존 네임스페이스
+    AdditionalGids     []uint32          `json:"additionalGids,omitempty"`
+    Umask              *uint32           `json:"umask,omitempty"`
+    Rlimits            []specRlimit      `json:"rlimits,omitempty"`
+    CapabilitySets     *specCapabilities `json:"capabilitySets,omitempty"` // 없으면 Capabilities를 모든 집합에 사용
+    AllowNewPrivileges bool              `json:"allowNewPrivileges,omitempty"`
+    Hooks              *specHooks        `json:"hooks,omitempty"`
+
+    hookState  []byte   // createRuntime 훅 이후 부모가 보내 준 상태 (컨테이너 훅의 입력)
+    syncSocket *os.File // initSyncFd
+}
+
+// nsinit에 넘기는 파일 디스크립터
+const (
+    initPipeFd      = 3
+    consoleSocketFd = 4
+    execFifoFd      = 5 // Bundle 모드만
+    initSyncFd      = 6 // Bundle 모드만
+)
+
+// nsinit : 새 네임스페이스 안에서 먼저 실행되어 루트 전환, 권한 축소, seccomp 설치 후 컨테이너 명령으로 exec
+// Go에서는 fork와 exec 사이에 코드를 실행할 수 없으므로 carte 자신을 다시 실행하여 처리
+var nsinitCmd = &cobra.Command{
+    Use:          "nsinit",
+    Hidden:       true,
+    Args:         cobra.NoArgs,
+    SilenceUsage: true,
+    RunE: func(cmd *cobra.Command, args []string) error {
+        return runInit()
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(nsinitCmd)
+}
+
+func runInit() error {
+    // 권한 변경과 필터 설치는 스레드 단위이므로 exec까지 같은 스레드에서 수행
+    runtime.LockOSThread()
+
+    pipe := os.NewFile(initPipeFd, "init-pipe")
+    var config initConfig
+    err := json.NewDecoder(pipe).Decode(&config)
+    pipe.Close()
+    if err != nil {
+        return fmt.Errorf("failed to read init config: %v", err)
+    }
+
+    // 경로로 지정된 기존 네임스페이스에 합류 (setns는 스레드 단위이므로 exec할 이 스레드에 적용됨)
+    // 마운트 네임스페이스 setns는 루트/작업 디렉토리(fs_struct)를 다른 스레드와 공유하면 EINVAL로 실패하므로
+    // 이 스레드의 fs_struct를 먼저 분리 (exec하면 이 스레드만 남음)
+    if len(config.Namespaces) > 0 {
+        if err := unix.Unshare(unix.CLONE_FS); err != nil {
+            return fmt.Errorf("failed to unshare filesystem attributes: %v", err)
+        }
+    }
+    for _, ns := range config.Namespaces {
+        if err := joinNamespace(ns); err != nil {
+            return err
+        }
+    }
+    for _, limit := range config.Rlimits {
+        // syscall.Setrlimit을 써야 Go 런타임이 exec 때 NOFILE을 원래 값으로 되돌리지 않음
+        if err := syscall.Setrlimit(rlimitTypes[limit.Type], &syscall.Rlimit{Cur: limit.Soft, Max: limit.Hard}); err != nil {
+            return fmt.Errorf("failed to set %s: %v", limit.Type, err)
+        }
+    }
+
+    // 새 UTS 네임스페이스의 호스트 이름 (exec은 컨테이너의 네임스페이스를 그대로 사용)
+    if config.Hostname != "" {
+        if err := unix.Sethostname([]byte(config.Hostname)); err != nil {
+            return fmt.Errorf("failed to set hostname: %v", err)
+        }
+    }
+
+    if config.Pivot {
+        if err := setupRootfs(&config); err != nil {
+            return err
+        }
+    } else if config.Root != "" {
+        if err := syscall.Chroot(config.Root); err != nil {
+            return fmt.Errorf("failed to chroot to %s: %v", config.Root, err)
+        }
+    }
+
+    if config.Console {
+        if err := setupConsole(); err != nil {
+            return err
+        }
+    }
+    if err := os.Chdir(config.Cwd); err != nil {
+        return fmt.Errorf("failed to change to working directory %s: %v", config.Cwd, err)
+    }
+
+    // 준비 완료를 알리고 runtime start까지 대기 (startContainer 훅은 권한을 줄이기 전에 실행)
+    if config.Bundle {
+        if err := waitRuntimeStart(&config); err != nil {
+            return err
+        }
+    }
+
+    // no_new_privs 없이 seccomp를 설치하려면 CAP_SYS_ADMIN이 필요하므로 권한을 줄이기 전에 설치
+    if config.AllowNewPrivileges && len(config.Seccomp) > 0 {
+        if err := installSeccompFilter(config.Seccomp); err != nil {
+            return err
+        }
+    }
+
+    // 허용하지 않은 권한은 bounding 집합에서 제거 (CAP_SETPCAP이 남아 있는 지금 수행)
+    if err := dropBoundingCapabilities(config.Capabilities); err != nil {
+        return err
+    }
+
+    // root가 아닌 사용자로 바뀌면 커널이 나머지 권한을 모두 비움 (집합별 권한이 있으면 유지한 뒤 다시 설정)
+    if config.CapabilitySets != nil && config.Uid != 0 {
+        if err := unix.Prctl(unix.PR_SET_KEEPCAPS, 1, 0, 0, 0); err != nil {
+            return fmt.Errorf("failed to keep capabilities: %v", err)
+        }
+    }
+    if config.Uid != 0 || config.Gid != 0 || len(config.AdditionalGids) > 0 {
+        groups := []int{}
+        for _, gid := range config.AdditionalGids {
+            groups = append(groups, int(gid))
+        }
+        if err := syscall.Setgroups(groups); err != nil {
+            return fmt.Errorf("failed to set groups: %v", err)
+        }
+        if err := syscall.Setgid(int(config.Gid)); err != nil {
+            return fmt.Errorf("failed to set gid %d: %v", config.Gid, err)
+        }
+        if err := syscall.Setuid(int(config.Uid)); err != nil {
+            return fmt.Errorf("failed to set uid %d: %v", config.Uid, err)
+        }
+    }
+    if config.CapabilitySets != nil {
+        if err := setCapabilitySets(config.CapabilitySets); err != nil {
+            return err
+        }
+    } else if config.Uid == 0 {
+        if err := setProcessCapabilities(config.Capabilities); err != nil {
+            return err
+        }
+    }
+
+    // setuid 실행 파일 등으로 권한을 다시 얻지 못하게 함 (권한 없이 seccomp 설치도 가능해짐)
+    if !config.AllowNewPrivileges {
+        if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+            return fmt.Errorf("failed to set no_new_privs: %v", err)
+        }
+        if err := installSeccompFilter(config.Seccomp); err != nil {
+            return err
+        }
+    }
+    if config.Umask != nil {
+        unix.Umask(int(*config.Umask))
+    }
+
+    if config.Init {
+        return runContainerInit(&config)
+    }
+    if err := syscall.Exec(config.Path, config.Args, config.Env); err != nil {
+        return fmt.Errorf("failed to exec %s: %v", config.Path, err)
+    }
+    return nil
+}
+
+// nsinit으로 실행할 명령 준비 (호출자가 SysProcAttr와 입출력을 설정)
+func newInitCommand() (*exec.Cmd, error) {
+    self, err := os.Executable()
+    if err != nil {
+        return nil, fmt.Errorf("failed to find carte executable: %v", err)
+    }
+    cmd := exec.Command(self, "nsinit")
+    cmd.Env = []string{} // 컨테이너 환경 변수는 exec 시점에 설정
+    return cmd, nil
+}
+
+// nsinit 프로세스를 시작하고 설정 전달 (설정 파이프는 항상 fd 3, 나머지 ExtraFiles는 그 뒤)
+func startInit(cmd *exec.Cmd, config *initConfig) error {
+    reader, writer, err := os.Pipe()
+    if err != nil {
+        return fmt.Errorf("failed to create init pipe: %v", err)
+    }
+    defer writer.Close()
+
+    cmd.ExtraFiles = append([]*os.File{reader}, cmd.ExtraFiles...)
+    err = cmd.Start()
+    reader.Close()
+    if err != nil {
+        return err
+    }
+
+    if err := json.NewEncoder(writer).Encode(config); err != nil {
+        cmd.Process.Kill()
+        cmd.Wait()
+        return fmt.Errorf("failed to send init config: %v", err)
+    }
+    return nil
+}
+
+// 컨테이너의 /dev/pts에서 PTY를 할당해 제어 터미널로 쓰고, master는 부모에게 전달
+func setupConsole() error {
+    socket := os.NewFile(consoleSocketFd, "console-socket")
+    defer socket.Close()
+
+    master, slave, err := openPty("/dev/pts/ptmx", "/dev/pts")
+    if err != nil {
+        return err
+    }
+    defer master.Close()
+    defer slave.Close()
+
+    if err := setControllingTerminal(slave); err != nil {
+        return err
+    }
+    return sendConsole(socket, master)
+}
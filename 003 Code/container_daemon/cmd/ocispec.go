@@ -0,0 +1,452 @@
+package cmd
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "syscall"
+
+    "golang.org/x/sys/unix"
+)
+
+// OCI runtime-spec의 번들 설정 (config.json) 중 carte runtime이 지원하는 항목
+type runtimeSpec struct {
+    OCIVersion  string            `json:"ociVersion"`
+    Process     *specProcess      `json:"process,omitempty"`
+    Root        *specRoot         `json:"root,omitempty"`
+    Hostname    string            `json:"hostname,omitempty"`
+    Mounts      []specMount       `json:"mounts,omitempty"`
+    Hooks       *specHooks        `json:"hooks,omitempty"`
+    Annotations map[string]string `json:"annotations,omitempty"`
+    Linux       *specLinux        `json:"linux,omitempty"`
+}
+
+type specProcess struct {
+    Terminal        bool              `json:"terminal,omitempty"`
+    User            specUser          `json:"user"`
+    Args            []string          `json:"args"`
+    Env             []string          `json:"env,omitempty"`
+    Cwd             string            `json:"cwd"`
+    Capabilities    *specCapabilities `json:"capabilities,omitempty"`
+    Rlimits         []specRlimit      `json:"rlimits,omitempty"`
+    NoNewPrivileges bool              `json:"noNewPrivileges,omitempty"`
+    OOMScoreAdj     *int              `json:"oomScoreAdj,omitempty"`
+}
+
+type specUser struct {
+    UID            uint32   `json:"uid"`
+    GID            uint32   `json:"gid"`
+    Umask          *uint32  `json:"umask,omitempty"`
+    AdditionalGids []uint32 `json:"additionalGids,omitempty"`
+}
+
+// specCapabilities : 집합별 권한 목록 (CAP_ 접두사 포함)
+type specCapabilities struct {
+    Bounding    []string `json:"bounding,omitempty"`
+    Effective   []string `json:"effective,omitempty"`
+    Inheritable []string `json:"inheritable,omitempty"`
+    Permitted   []string `json:"permitted,omitempty"`
+    Ambient     []string `json:"ambient,omitempty"`
+}
+
+type specRlimit struct {
+    Type string `json:"type"`
+    Hard uint64 `json:"hard"`
+    Soft uint64 `json:"soft"`
+}
+
+type specRoot struct {
+    Path     string `json:"path"`
+    Readonly bool   `json:"readonly,omitempty"`
+}
+
+type specMount struct {
+    Destination string   `json:"destination"`
+    Type        string   `json:"type,omitempty"`
+    Source      string   `json:"source,omitempty"`
+    Options     []string `json:"options,omitempty"`
+}
+
+type specHook struct {
+    Path    string   `json:"path"`
+    Args    []string `json:"args,omitempty"`
+    Env     []string `json:"env,omitempty"`
+    Timeout *int     `json:"timeout,omitempty"`
+}
+
+// specHooks : 수명 주기 단계별 훅
+// prestart/createRuntime/poststart/poststop은 런타임 네임스페이스, createContainer/startContainer는 컨테이너 안에서 실행
+type specHooks struct {
+    Prestart        []specHook `json:"prestart,omitempty"`
+    CreateRuntime   []specHook `json:"createRuntime,omitempty"`
+    CreateContainer []specHook `json:"createContainer,omitempty"`
+    StartContainer  []specHook `json:"startContainer,omitempty"`
+    Poststart       []specHook `json:"poststart,omitempty"`
+    Poststop        []specHook `json:"poststop,omitempty"`
+}
+
+type specLinux struct {
+    UIDMappings       []specIDMapping   `json:"uidMappings,omitempty"`
+    GIDMappings       []specIDMapping   `json:"gidMappings,omitempty"`
+    Sysctl            map[string]string `json:"sysctl,omitempty"`
+    Resources         *specResources    `json:"resources,omitempty"`
+    CgroupsPath       string            `json:"cgroupsPath,omitempty"`
+    Namespaces        []specNamespace   `json:"namespaces,omitempty"`
+    Devices           []specDevice      `json:"devices,omitempty"`
+    Seccomp           *seccompProfile   `json:"seccomp,omitempty"`
+    RootfsPropagation string            `json:"rootfsPropagation,omitempty"`
+    MaskedPaths       []string          `json:"maskedPaths,omitempty"`
+    ReadonlyPaths     []string          `json:"readonlyPaths,omitempty"`
+}
+
+type specIDMapping struct {
+    ContainerID uint32 `json:"containerID"`
+    HostID      uint32 `json:"hostID"`
+    Size        uint32 `json:"size"`
+}
+
+type specNamespace struct {
+    Type string `json:"type"`
+    Path string `json:"path,omitempty"`
+}
+
+type specDevice struct {
+    Type     string       `json:"type"` // c, b, u, p
+    Path     string       `json:"path"`
+    Major    int64        `json:"major,omitempty"`
+    Minor    int64        `json:"minor,omitempty"`
+    FileMode *os.FileMode `json:"fileMode,omitempty"`
+    UID      *uint32      `json:"uid,omitempty"`
+    GID      *uint32      `json:"gid,omitempty"`
+}
+
+type specResources struct {
+    Memory *struct {
+        Limit *int64 `json:"limit,omitempty"`
+        Swap  *int64 `json:"swap,omitempty"`
+    } `json:"memory,omitempty"`
+    CPU *struct {
+        Shares *uint64 `json:"shares,omitempty"`
+        Quota  *int64  `json:"quota,omitempty"`
+        Period *uint64 `json:"period,omitempty"`
+    } `json:"cpu,omitempty"`
+    Pids *struct {
+        Limit int64 `json:"limit"`
+    } `json:"pids,omitempty"`
+    BlockIO *struct {
+        Weight *uint16 `json:"weight,omitempty"`
+    } `json:"blockIO,omitempty"`
+}
+
+// 네임스페이스 종류별 clone 플래그
+var namespaceFlags = map[string]uintptr{
+    "pid":     syscall.CLONE_NEWPID,
+    "network": syscall.CLONE_NEWNET,
+    "mount":   syscall.CLONE_NEWNS,
+    "ipc":     syscall.CLONE_NEWIPC,
+    "uts":     syscall.CLONE_NEWUTS,
+    "user":    syscall.CLONE_NEWUSER,
+    "cgroup":  syscall.CLONE_NEWCGROUP,
+}
+
+var rlimitTypes = map[string]int{
+    "RLIMIT_AS":         unix.RLIMIT_AS,
+    "RLIMIT_CORE":       unix.RLIMIT_CORE,
+    "RLIMIT_CPU":        unix.RLIMIT_CPU,
+    "RLIMIT_DATA":       unix.RLIMIT_DATA,
+    "RLIMIT_FSIZE":      unix.RLIMIT_FSIZE,
+    "RLIMIT_LOCKS":      unix.RLIMIT_LOCKS,
+    "RLIMIT_MEMLOCK":    unix.RLIMIT_MEMLOCK,
+    "RLIMIT_MSGQUEUE":   unix.RLIMIT_MSGQUEUE,
+    "RLIMIT_NICE":       unix.RLIMIT_NICE,
+    "RLIMIT_NOFILE":     unix.RLIMIT_NOFILE,
+    "RLIMIT_NPROC":      unix.RLIMIT_NPROC,
+    "RLIMIT_RSS":        unix.RLIMIT_RSS,
+    "RLIMIT_RTPRIO":     unix.RLIMIT_RTPRIO,
+    "RLIMIT_RTTIME":     unix.RLIMIT_RTTIME,
+    "RLIMIT_SIGPENDING": unix.RLIMIT_SIGPENDING,
+    "RLIMIT_STACK":      unix.RLIMIT_STACK,
+}
+
+// 마운트 옵션 중 플래그로 바꾸는 것 (false면 플래그를 끔), 나머지는 파일시스템 데이터로 전달
+var mountOptionFlags = map[string]struct {
+    clear bool
+    flag  uintptr
+}{
+    "ro":            {false, syscall.MS_RDONLY},
+    "rw":            {true, syscall.MS_RDONLY},
+    "nosuid":        {false, syscall.MS_NOSUID},
+    "suid":          {true, syscall.MS_NOSUID},
+    "nodev":         {false, syscall.MS_NODEV},
+    "dev":           {true, syscall.MS_NODEV},
+    "noexec":        {false, syscall.MS_NOEXEC},
+    "exec":          {true, syscall.MS_NOEXEC},
+    "sync":          {false, syscall.MS_SYNCHRONOUS},
+    "async":         {true, syscall.MS_SYNCHRONOUS},
+    "dirsync":       {false, syscall.MS_DIRSYNC},
+    "remount":       {false, syscall.MS_REMOUNT},
+    "mand":          {false, syscall.MS_MANDLOCK},
+    "nomand":        {true, syscall.MS_MANDLOCK},
+    "atime":         {true, syscall.MS_NOATIME},
+    "noatime":       {false, syscall.MS_NOATIME},
+    "diratime":      {true, syscall.MS_NODIRATIME},
+    "nodiratime":    {false, syscall.MS_NODIRATIME},
+    "bind":          {false, syscall.MS_BIND},
+    "rbind":         {false, syscall.MS_BIND | syscall.MS_REC},
+    "relatime":      {false, syscall.MS_RELATIME},
+    "norelatime":    {true, syscall.MS_RELATIME},
+    "strictatime":   {false, syscall.MS_STRICTATIME},
+    "nostrictatime": {true, syscall.MS_STRICTATIME},
+}
+
+var propagationFlags = map[string]uintptr{
+    "private":     syscall.MS_PRIVATE,
+    "rprivate":    syscall.MS_PRIVATE | syscall.MS_REC,
+    "shared":      syscall.MS_SHARED,
+    "rshared":     syscall.MS_SHARED | syscall.MS_REC,
+    "slave":       syscall.MS_SLAVE,
+    "rslave":      syscall.MS_SLAVE | syscall.MS_REC,
+    "unbindable":  syscall.MS_UNBINDABLE,
+    "runbindable": syscall.MS_UNBINDABLE | syscall.MS_REC,
+}
+
+// 마운트 옵션 해석: 마운트 플래그, 전파 플래그, 파일시스템 데이터
+func parseMountOptions(options []string) (uintptr, uintptr, string) {
+    var flags, propagation uintptr
+    var data []string
+    for _, opt := range options {
+        if f, ok := mountOptionFlags[opt]; ok {
+            if f.clear {
+                flags &^= f.flag
+            } else {
+                flags |= f.flag
+            }
+        } else if p, ok := propagationFlags[opt]; ok {
+            propagation |= p
+        } else {
+            data = append(data, opt)
+        }
+    }
+    return flags, propagation, strings.Join(data, ",")
+}
+
+// 번들의 config.json 읽기와 기본 검증
+func loadBundleSpec(bundle string) (*runtimeSpec, error) {
+    data, err := os.ReadFile(filepath.Join(bundle, "config.json"))
+    if err != nil {
+        return nil, fmt.Errorf("failed to read bundle config: %v", err)
+    }
+    var spec runtimeSpec
+    if err := json.Unmarshal(data, &spec); err != nil {
+        return nil, fmt.Errorf("failed to parse bundle config: %v", err)
+    }
+
+    if spec.OCIVersion == "" {
+        return nil, fmt.Errorf("bundle config has no ociVersion")
+    }
+    if spec.Root == nil || spec.Root.Path == "" {
+        return nil, fmt.Errorf("bundle config has no root.path")
+    }
+    if spec.Process == nil || len(spec.Process.Args) == 0 {
+        return nil, fmt.Errorf("bundle config has no process.args")
+    }
+    if !filepath.IsAbs(spec.Process.Cwd) {
+        return nil, fmt.Errorf("process.cwd %q must be absolute", spec.Process.Cwd)
+    }
+    if spec.Linux == nil {
+        spec.Linux = &specLinux{}
+    }
+    if spec.Hooks == nil {
+        spec.Hooks = &specHooks{}
+    }
+    for _, m := range spec.Mounts {
+        if !filepath.IsAbs(m.Destination) {
+            return nil, fmt.Errorf("mount destination %q must be absolute", m.Destination)
+        }
+    }
+    return &spec, nil
+}
+
+// 상대 경로는 번들 디렉토리 기준
+func bundlePath(bundle, path string) string {
+    if filepath.IsAbs(path) {
+        return filepath.Clean(path)
+    }
+    return filepath.Join(bundle, path)
+}
+
+// 번들 설정을 nsinit 설정과 clone 속성으로 변환
+func buildRuntimeInit(bundle string, spec *runtimeSpec) (*initConfig, *syscall.SysProcAttr, error) {
+    process := spec.Process
+    root := bundlePath(bundle, spec.Root.Path)
+    if info, err := os.Stat(root); err != nil || !info.IsDir() {
+        return nil, nil, fmt.Errorf("root filesystem %s does not exist", root)
+    }
+
+    attr := &syscall.SysProcAttr{}
+    var join []specNamespace
+    for _, ns := range spec.Linux.Namespaces {
+        flag, ok := namespaceFlags[ns.Type]
+        if !ok {
+            return nil, nil, fmt.Errorf("unknown namespace type %q", ns.Type)
+        }
+        if ns.Path == "" {
+            attr.Cloneflags |= flag
+            continue
+        }
+        // setns는 스레드 단위로 적용되므로 exec할 스레드에서 바꿀 수 있는 것만 지원
+        // (mount/user는 단일 스레드, pid는 자식에만 적용되어 Go 프로세스에서 합류할 수 없음)
+        switch ns.Type {
+        case "network", "ipc", "uts":
+            join = append(join, ns)
+        default:
+            return nil, nil, fmt.Errorf("joining an existing %s namespace is not supported", ns.Type)
+        }
+    }
+    if attr.Cloneflags&syscall.CLONE_NEWNS == 0 {
+        return nil, nil, fmt.Errorf("a new mount namespace is required")
+    }
+    if attr.Cloneflags&syscall.CLONE_NEWUSER != 0 {
+        for _, m := range spec.Linux.UIDMappings {
+            attr.UidMappings = append(attr.UidMappings, syscall.SysProcIDMap{ContainerID: int(m.ContainerID), HostID: int(m.HostID), Size: int(m.Size)})
+        }
+        for _, m := range spec.Linux.GIDMappings {
+            attr.GidMappings = append(attr.GidMappings, syscall.SysProcIDMap{ContainerID: int(m.ContainerID), HostID: int(m.HostID), Size: int(m.Size)})
+        }
+        attr.GidMappingsEnableSetgroups = true
+    }
+    if process.Terminal {
+        attr.Setsid = true // 제어 터미널을 가질 새 세션
+    }
+
+    // 바인드 마운트 원본의 상대 경로는 번들 기준
+    var mounts []specMount
+    for _, m := range spec.Mounts {
+        flags, _, _ := parseMountOptions(m.Options)
+        if flags&syscall.MS_BIND != 0 || m.Type == "bind" {
+            m.Source = bundlePath(bundle, m.Source)
+        }
+        mounts = append(mounts, m)
+    }
+
+    for _, limit := range process.Rlimits {
+        if _, ok := rlimitTypes[limit.Type]; !ok {
+            return nil, nil, fmt.Errorf("unknown rlimit type %q", limit.Type)
+        }
+    }
+    var propagation uintptr
+    if spec.Linux.RootfsPropagation != "" {
+        var ok bool
+        if propagation, ok = propagationFlags[spec.Linux.RootfsPropagation]; !ok {
+            return nil, nil, fmt.Errorf("invalid rootfsPropagation %q", spec.Linux.RootfsPropagation)
+        }
+    }
+
+    caps := &specCapabilities{}
+    if process.Capabilities != nil {
+        caps = &specCapabilities{
+            Bounding:    knownCapabilities(process.Capabilities.Bounding),
+            Effective:   knownCapabilities(process.Capabilities.Effective),
+            Inheritable: knownCapabilities(process.Capabilities.Inheritable),
+            Permitted:   knownCapabilities(process.Capabilities.Permitted),
+            Ambient:     knownCapabilities(process.Capabilities.Ambient),
+        }
+    }
+
+    var filter []unix.SockFilter
+    if spec.Linux.Seccomp != nil {
+        var err error
+        if filter, err = compileSeccompProfile(spec.Linux.Seccomp, caps.Bounding); err != nil {
+            return nil, nil, err
+        }
+    }
+
+    path, err := lookPathInContainer(root, process.Args[0], process.Env)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    config := &initConfig{
+        Root:               root,
+        Pivot:              true,
+        Hostname:           spec.Hostname,
+        ReadonlyRoot:       spec.Root.Readonly,
+        Mounts:             mounts,
+        Devices:            spec.Linux.Devices,
+        Sysctl:             spec.Linux.Sysctl,
+        RootPropagation:    propagation,
+        MaskedPaths:        spec.Linux.MaskedPaths,
+        ReadonlyPaths:      spec.Linux.ReadonlyPaths,
+        Namespaces:         join,
+        Path:               path,
+        Args:               process.Args,
+        Env:                process.Env,
+        Cwd:                process.Cwd,
+        Uid:                process.User.UID,
+        Gid:                process.User.GID,
+        AdditionalGids:     process.User.AdditionalGids,
+        Umask:              process.User.Umask,
+        Rlimits:            process.Rlimits,
+        Capabilities:       caps.Bounding,
+        CapabilitySets:     caps,
+        AllowNewPrivileges: !process.NoNewPrivileges,
+        Seccomp:            filter,
+        Console:            process.Terminal,
+        Hooks:              spec.Hooks,
+        Bundle:             true,
+    }
+    return config, attr, nil
+}
+
+// 알 수 없는 권한은 경고 후 무시 (다른 커널용 설정과 호환)
+func knownCapabilities(names []string) []string {
+    var caps []string
+    for _, name := range names {
+        normalized, err := normalizeCapability(name)
+        if err != nil || normalized == "ALL" {
+            fmt.Fprintf(os.Stderr, "Warning: ignoring unknown capability %q\n", name)
+            continue
+        }
+        caps = append(caps, normalized)
+    }
+    return caps
+}
+
+// OCI 자원 설정을 컨테이너 cgroup 제한으로 변환
+func specResourcesToContainer(spec *specResources) containerResources {
+    var res containerResources
+    if spec == nil {
+        return res
+    }
+    if spec.Memory != nil {
+        if spec.Memory.Limit != nil && *spec.Memory.Limit > 0 {
+            res.Memory = *spec.Memory.Limit
+        }
+        // 스왑 제한은 메모리+스왑 합계이므로 메모리 제한이 있을 때만 의미가 있음
+        if spec.Memory.Swap != nil && (*spec.Memory.Swap == -1 || res.Memory > 0 && *spec.Memory.Swap >= res.Memory) {
+            res.MemorySwap = *spec.Memory.Swap
+        }
+    }
+    if spec.CPU != nil {
+        if spec.CPU.Quota != nil && *spec.CPU.Quota > 0 {
+            period := uint64(cpuPeriodMicros)
+            if spec.CPU.Period != nil && *spec.CPU.Period > 0 {
+                period = *spec.CPU.Period
+            }
+            res.CPUs = float64(*spec.CPU.Quota) / float64(period)
+        }
+        if spec.CPU.Shares != nil && *spec.CPU.Shares >= 2 {
+            res.CPUShares = int64(*spec.CPU.Shares)
+        }
+    }
+    if spec.Pids != nil && spec.Pids.Limit > 0 {
+        res.PidsLimit = spec.Pids.Limit
+    }
+    if spec.BlockIO != nil && spec.BlockIO.Weight != nil && *spec.BlockIO.Weight >= 10 {
+        // cgroup v1 blkio weight(10~1000)를 v2 io.weight(1~10000)로 변환
+        res.IOWeight = 1 + (int64(*spec.BlockIO.Weight)-10)*9999/990
+    }
+    return res
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+    "encoding/json"
+    "os"
+    "path/filepath"
+    "reflect"
+    "syscall"
+    "testing"
+)
+
+func TestParseMountOptions(t *testing.T) {
+    tests := []struct {
+        name            string
+        options         []string
+        wantFlags       uintptr
+        wantPropagation uintptr
+        wantData        string
+    }{
+        {name: "none"},
+        {
+            name:      "flags",
+            options:   []string{"nosuid", "noexec", "nodev", "ro"},
+            wantFlags: syscall.MS_NOSUID | syscall.MS_NOEXEC | syscall.MS_NODEV | syscall.MS_RDONLY,
+        },
+        {
+            name:      "later option clears an earlier one",
+            options:   []string{"ro", "nosuid", "rw"},
+            wantFlags: syscall.MS_NOSUID,
+        },
+        {
+            name:            "bind with propagation",
+            options:         []string{"rbind", "rprivate"},
+            wantFlags:       syscall.MS_BIND | syscall.MS_REC,
+            wantPropagation: syscall.MS_PRIVATE | syscall.MS_REC,
+        },
+        {
+            name:      "filesystem data",
+            options:   []string{"nosuid", "mode=755", "size=65536k"},
+            wantFlags: syscall.MS_NOSUID,
+            wantData:  "mode=755,size=65536k",
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            flags, propagation, data := parseMountOptions(tt.options)
+            if flags != tt.wantFlags || propagation != tt.wantPropagation || data != tt.wantData {
+                t.Fatalf("parseMountOptions(%q) = %#x, %#x, %q, want %#x, %#x, %q",
+                    tt.options, flags, propagation, data, tt.wantFlags, tt.wantPropagation, tt.wantData)
+            }
+        })
+    }
+}
+
+func TestLoadBundleSpec(t *testing.T) {
+    tests := []struct {
+        name    string
+        config  string
+        wantErr bool
+    }{
+        {name: "minimal", config: `{"ociVersion": "1.0.2", "root": {"path": "rootfs"}, "process": {"args": ["sh"], "cwd": "/"}}`},
+        {name: "no ociVersion", config: `{"root": {"path": "rootfs"}, "process": {"args": ["sh"], "cwd": "/"}}`, wantErr: true},
+        {name: "no root", config: `{"ociVersion": "1.0.2", "process": {"args": ["sh"], "cwd": "/"}}`, wantErr: true},
+        {name: "no args", config: `{"ociVersion": "1.0.2", "root": {"path": "rootfs"}, "process": {"cwd": "/"}}`, wantErr: true},
+        {name: "relative cwd", config: `{"ociVersion": "1.0.2", "root": {"path": "rootfs"}, "process": {"args": ["sh"], "cwd": "tmp"}}`, wantErr: true},
+        {
+            name:    "relative mount destination",
+            config:  `{"ociVersion": "1.0.2", "root": {"path": "rootfs"}, "process": {"args": ["sh"], "cwd": "/"}, "mounts": [{"destination": "proc", "type": "proc"}]}`,
+            wantErr: true,
+        },
+        {name: "invalid json", config: `{"ociVersion": `, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            bundle := t.TempDir()
+            if err := os.WriteFile(filepath.Join(bundle, "config.json"), []byte(tt.config), 0644); err != nil {
+                t.Fatal(err)
+            }
+            spec, err := loadBundleSpec(bundle)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("loadBundleSpec() error = %v, wantErr %v", err, tt.wantErr)
+            }
+            // 생략된 linux, hooks는 빈 값으로 채워 호출하는 쪽이 nil 검사를 하지 않아도 됨
+            if !tt.wantErr && (spec.Linux == nil || spec.Hooks == nil) {
+                t.Fatalf("loadBundleSpec() left linux or hooks nil")
+            }
+        })
+    }
+
+    if _, err := loadBundleSpec(t.TempDir()); err == nil {
+        t.Fatalf("loadBundleSpec() without config.json succeeded")
+    }
+}
+
+func TestBundlePath(t *testing.T) {
+    tests := []struct {
+        path string
+        want string
+    }{
+        {path: "rootfs", want: "/bundles/web/rootfs"},
+        {path: "../shared/rootfs", want: "/bundles/shared/rootfs"},
+        {path: "/var/lib/rootfs/", want: "/var/lib/rootfs"},
+    }
+
+    for _, tt := range tests {
+        if got := bundlePath("/bundles/web", tt.path); got != tt.want {
+            t.Fatalf("bundlePath(%q) = %q, want %q", tt.path, got, tt.want)
+        }
+    }
+}
+
+func TestRuntimeCgroupPath(t *testing.T) {
+    tests := []struct {
+        cgroupsPath string
+        want        string
+    }{
+        {cgroupsPath: "", want: "/sys/fs/cgroup/carte-runtime/web"},
+        {cgroupsPath: "/pods/web", want: "/sys/fs/cgroup/pods/web"},
+        {cgroupsPath: "pods/web", want: "/sys/fs/cgroup/pods/web"},
+        {cgroupsPath: "../../etc", want: "/sys/fs/cgroup/etc"},
+    }
+
+    for _, tt := range tests {
+        if got := runtimeCgroupPath("web", tt.cgroupsPath); got != tt.want {
+            t.Fatalf("runtimeCgroupPath(%q) = %q, want %q", tt.cgroupsPath, got, tt.want)
+        }
+    }
+}
+
+func TestKnownCapabilities(t *testing.T) {
+    got := knownCapabilities([]string{"CAP_CHOWN", "net_bind_service", "CAP_NOT_A_CAP", "ALL"})
+    if want := []string{"CAP_CHOWN", "CAP_NET_BIND_SERVICE"}; !reflect.DeepEqual(got, want) {
+        t.Fatalf("knownCapabilities() = %q, want %q", got, want)
+    }
+}
+
+func TestSpecResourcesToContainer(t *testing.T) {
+    tests := []struct {
+        name      string
+        resources string
+        want      containerResources
+    }{
+        {name: "none", resources: `{}`},
+        {
+            name:      "memory and swap",
+            resources: `{"memory": {"limit": 268435456, "swap": 536870912}}`,
+            want:      containerResources{Memory: 268435456, MemorySwap: 536870912},
+        },
+        {
+            name:      "unlimited swap",
+            resources: `{"memory": {"limit": 268435456, "swap": -1}}`,
+            want:      containerResources{Memory: 268435456, MemorySwap: -1},
+        },
+        {
+            // 메모리 제한이 없거나 합계가 메모리보다 작은 스왑 제한은 무시
+            name:      "swap without memory",
+            resources: `{"memory": {"swap": 536870912}}`,
+        },
+        {
+            name:      "swap below memory",
+            resources: `{"memory": {"limit": 268435456, "swap": 1024}}`,
+            want:      containerResources{Memory: 268435456},
+        },
+        {
+            name:      "cpu quota with default period",
+            resources: `{"cpu": {"quota": 50000, "shares": 512}}`,
+            want:      containerResources{CPUs: 0.5, CPUShares: 512},
+        },
+        {
+            name:      "cpu quota with period",
+            resources: `{"cpu": {"quota": 300000, "period": 200000}}`,
+            want:      containerResources{CPUs: 1.5},
+        },
+        {
+            name:      "pids and io weight",
+            resources: `{"pids": {"limit": 100}, "blockIO": {"weight": 1000}}`,
+            want:      containerResources{PidsLimit: 100, IOWeight: 10000},
+        },
+        {
+            name:      "minimum io weight",
+            resources: `{"blockIO": {"weight": 10}}`,
+            want:      containerResources{IOWeight: 1},
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var spec specResources
+            if err := json.Unmarshal([]byte(tt.resources), &spec); err != nil {
+                t.Fatal(err)
+            }
+            if got := specResourcesToContainer(&spec); got != tt.want {
+                t.Fatalf("specResourcesToContainer(%s) = %+v, want %+v", tt.resources, got, tt.want)
+            }
+        })
+    }
+
+    if got := specResourcesToContainer(nil); got != (containerResources{}) {
+        t.Fatalf("specResourcesToContainer(nil) = %+v, want no limits", got)
+    }
+}
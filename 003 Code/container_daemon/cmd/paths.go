@@ -0,0 +1,71 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+const maxSymlinkDepth = 255
+
+// 컨테이너 루트 안의 경로를 심볼릭 링크까지 따라가며 해석하되, 루트 밖으로 벗어나지 않게 함
+// (절대 경로 링크는 컨테이너 루트 기준, ".."는 루트에서 멈춤)
+func resolveInRoot(root, unsafePath string) (string, error) {
+    root = filepath.Clean(root)
+
+    var resolved string // root 기준 상대 경로
+    remaining := filepath.ToSlash(unsafePath)
+    links := 0
+
+    for remaining != "" {
+        var part string
+        if i := strings.IndexByte(remaining, '/'); i >= 0 {
+            part, remaining = remaining[:i], remaining[i+1:]
+        } else {
+            part, remaining = remaining, ""
+        }
+
+        switch part {
+        case "", ".":
+            continue
+        case "..":
+            resolved = filepath.Dir(resolved)
+            if resolved == "." || resolved == "/" {
+                resolved = ""
+            }
+            continue
+        }
+
+        next := filepath.Join(resolved, part)
+        info, err := os.Lstat(filepath.Join(root, next))
+        if err != nil {
+            if os.IsNotExist(err) {
+                // 아직 없는 경로는 그대로 이어 붙임
+                resolved = next
+                continue
+            }
+            return "", err
+        }
+
+        if info.Mode()&os.ModeSymlink == 0 {
+            resolved = next
+            continue
+        }
+
+        links++
+        if links > maxSymlinkDepth {
+            return "", fmt.Errorf("too many levels of symbolic links in %s", unsafePath)
+        }
+        target, err := os.Readlink(filepath.Join(root, next))
+        if err != nil {
+            return "", err
+        }
+        if filepath.IsAbs(target) {
+            resolved = ""
+        }
+        remaining = target + "/" + remaining
+    }
+
+    return filepath.Join(root, resolved), nil
+}
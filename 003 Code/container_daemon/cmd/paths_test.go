@@ -0,0 +1,75 @@
+package cmd
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestResolveInRoot(t *testing.T) {
+    root := t.TempDir()
+    for _, dir := range []string{"etc", "usr/lib", "var/data"} {
+        if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+            t.Fatal(err)
+        }
+    }
+    links := map[string]string{
+        "lib":           "usr/lib",          // 상대 경로 링크
+        "abs":           "/var/data",        // 절대 경로 링크는 컨테이너 루트 기준
+        "escape":        "../../../../etc",  // 루트 위로 올라가는 링크
+        "absescape":     "/../../etc",       // 절대 경로에서 루트 위로
+        "usr/lib/up":    "../../etc",        // 중간 디렉토리 안의 상대 링크
+        "chain":         "lib/up",           // 링크를 거치는 링크
+        "loop1":         "loop2",
+        "loop2":         "loop1",
+        "dangling":      "/nonexistent/dir",
+        "var/data/self": ".",
+    }
+    for name, target := range links {
+        if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    tests := []struct {
+        path    string
+        want    string // root 기준 경로
+        wantErr bool
+    }{
+        {path: "/etc", want: "/etc"},
+        {path: "etc/passwd", want: "/etc/passwd"},
+        {path: "/", want: "/"},
+        {path: "", want: "/"},
+        {path: "/lib/x", want: "/usr/lib/x"},
+        {path: "/abs/file", want: "/var/data/file"},
+        {path: "/../../etc", want: "/etc"},
+        {path: "/usr/../../..", want: "/"},
+        {path: "/escape/passwd", want: "/etc/passwd"},
+        {path: "/absescape/passwd", want: "/etc/passwd"},
+        {path: "/usr/lib/up/hosts", want: "/etc/hosts"},
+        {path: "/chain/hosts", want: "/etc/hosts"},
+        {path: "/dangling/file", want: "/nonexistent/dir/file"},
+        {path: "/var/data/self/self/x", want: "/var/data/x"},
+        // 링크를 따라간 뒤의 ".."는 링크 대상의 상위 디렉토리
+        {path: "/lib/../x", want: "/usr/x"},
+        {path: "/loop1/x", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.path, func(t *testing.T) {
+            got, err := resolveInRoot(root, tt.path)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("resolveInRoot(%q) = %s, want error", tt.path, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatalf("resolveInRoot(%q) error = %v", tt.path, err)
+            }
+            if want := filepath.Join(root, tt.want); got != want {
+                t.Fatalf("resolveInRoot(%q) = %s, want %s", tt.path, got, want)
+            }
+        })
+    }
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+var pauseCmd = &cobra.Command{
+    Use:   "pause [container]",
+    Short: "Suspend all processes in a container",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        return pauseContainer(containerName)
+    },
+}
+
+var unpauseCmd = &cobra.Command{
+    Use:   "unpause [container]",
+    Short: "Resume all processes in a paused container",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        return unpauseContainer(containerName)
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(pauseCmd)
+    rootCmd.AddCommand(unpauseCmd)
+}
+
+// cgroup freezer로 컨테이너의 모든 프로세스를 멈춤 (연결과 메모리 상태는 그대로 유지)
+func pauseContainer(containerName string) error {
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return err
+    }
+    switch state.Status {
+    case statusPaused:
+        return fmt.Errorf("container %s is already paused", state.Name)
+    case statusRunning:
+    default:
+        return fmt.Errorf("container %s is not running", state.Name)
+    }
+
+    if err := freezeCgroup(containerName, true); err != nil {
+        return err
+    }
+    if err := setContainerStatus(containerName, statusPaused); err != nil {
+        // 상태를 기록하지 못했으면 다시 풀어 기록과 실제를 맞춤
+        freezeCgroup(containerName, false)
+        return err
+    }
+    recordContainerEvent(containerName, "pause", nil)
+    fmt.Printf("Container %s paused\n", state.Name)
+    return nil
+}
+
+func unpauseContainer(containerName string) error {
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return err
+    }
+    if state.Status != statusPaused {
+        return fmt.Errorf("container %s is not paused", state.Name)
+    }
+
+    if err := freezeCgroup(containerName, false); err != nil {
+        return err
+    }
+    if err := setContainerStatus(containerName, statusRunning); err != nil {
+        return err
+    }
+    recordContainerEvent(containerName, "unpause", nil)
+    fmt.Printf("Container %s unpaused\n", state.Name)
+    return nil
+}
+
+// 컨테이너 cgroup을 멈추거나 다시 풀어 줌
+func freezeCgroup(containerName string, freeze bool) error {
+    action := "thaw"
+    if freeze {
+        action = "freeze"
+    }
+    if err := setCgroupFreeze(containerCgroupPath(containerName), freeze, 10*time.Second); err != nil {
+        return fmt.Errorf("failed to %s container %s: %v", action, containerName, err)
+    }
+    return nil
+}
+
+// cgroup.freeze에 쓰고 cgroup.events의 frozen 값이 바뀔 때까지 대기
+func setCgroupFreeze(cgroupPath string, freeze bool, timeout time.Duration) error {
+    value, want, target := "0", uint64(0), "thawed"
+    if freeze {
+        value, want, target = "1", 1, "frozen"
+    }
+
+    if err := os.WriteFile(filepath.Join(cgroupPath, "cgroup.freeze"), []byte(value), 0644); err != nil {
+        return fmt.Errorf("failed to write cgroup.freeze: %v", err)
+    }
+
+    // 프로세스가 커널 안에서 대기 중이면 멈추는 데 시간이 걸릴 수 있음
+    deadline := time.Now().Add(timeout)
+    for {
+        events, err := readKeyValueFile(filepath.Join(cgroupPath, "cgroup.events"))
+        if err != nil {
+            return fmt.Errorf("failed to read cgroup.events: %v", err)
+        }
+        if events["frozen"] == want {
+            return nil
+        }
+        if time.Now().After(deadline) {
+            return fmt.Errorf("timed out waiting for the cgroup to become %s", target)
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+}
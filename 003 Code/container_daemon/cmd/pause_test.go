@@ -0,0 +1,43 @@
+package cmd
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+func TestSetCgroupFreeze(t *testing.T) {
+    tests := []struct {
+        freeze  bool
+        frozen  string // cgroup.events의 frozen 값
+        want    string // cgroup.freeze에 쓰는 값
+        wantErr bool
+    }{
+        {freeze: true, frozen: "1", want: "1"},
+        {freeze: false, frozen: "0", want: "0"},
+        {freeze: true, frozen: "0", want: "1", wantErr: true}, // 멈추지 않으면 시간 초과
+        {freeze: false, frozen: "1", want: "0", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        dir := t.TempDir()
+        events := "populated 1\nfrozen " + tt.frozen + "\n"
+        if err := os.WriteFile(filepath.Join(dir, "cgroup.events"), []byte(events), 0644); err != nil {
+            t.Fatal(err)
+        }
+
+        err := setCgroupFreeze(dir, tt.freeze, 50*time.Millisecond)
+        if (err != nil) != tt.wantErr {
+            t.Fatalf("setCgroupFreeze(%v) with frozen %s error = %v, wantErr %v", tt.freeze, tt.frozen, err, tt.wantErr)
+        }
+        if data, _ := os.ReadFile(filepath.Join(dir, "cgroup.freeze")); string(data) != tt.want {
+            t.Fatalf("setCgroupFreeze(%v) wrote %q to cgroup.freeze, want %q", tt.freeze, data, tt.want)
+        }
+    }
+
+    // cgroup이 없으면 (컨테이너 종료) 바로 오류
+    if err := setCgroupFreeze(filepath.Join(t.TempDir(), "missing"), true, time.Second); err == nil {
+        t.Fatalf("setCgroupFreeze() on a missing cgroup succeeded")
+    }
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "os/signal"
+    "syscall"
+
+    "golang.org/x/sys/unix"
+)
+
+// ptmx에서 의사 터미널(PTY) 쌍을 할당, slave는 ptsDir 아래에 생김
+// 컨테이너 전용 devpts 인스턴스를 사용하면 컨테이너 안에서도 /dev/pts/N 으로 보임
+func openPty(ptmxPath, ptsDir string) (*os.File, *os.File, error) {
+    master, err := os.OpenFile(ptmxPath, os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+    if err != nil {
+        return nil, nil, fmt.Errorf("failed to open %s: %v", ptmxPath, err)
+    }
+
+    // slave 잠금 해제 (unlockpt)
+    if err := unix.IoctlSetPointerInt(int(master.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+        master.Close()
+        return nil, nil, fmt.Errorf("failed to unlock pty: %v", err)
+    }
+
+    // slave 번호 조회 (ptsname)
+    n, err := unix.IoctlGetUint32(int(master.Fd()), unix.TIOCGPTN)
+    if err != nil {
+        master.Close()
+        return nil, nil, fmt.Errorf("failed to get pty number: %v", err)
+    }
+
+    slave, err := os.OpenFile(fmt.Sprintf("%s/%d", ptsDir, n), os.O_RDWR|syscall.O_NOCTTY, 0)
+    if err != nil {
+        master.Close()
+        return nil, nil, fmt.Errorf("failed to open pty slave: %v", err)
+    }
+
+    return master, slave, nil
+}
+
+func isTerminal(f *os.File) bool {
+    _, err := unix.IoctlGetTermios(int(f.Fd()), unix.TCGETS)
+    return err == nil
+}
+
+// 터미널을 raw 모드로 바꾸고 원래 상태로 되돌리는 함수를 반환
+func setRawTerminal(f *os.File) (func(), error) {
+    fd := int(f.Fd())
+    oldState, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+    if err != nil {
+        return nil, fmt.Errorf("failed to get terminal state: %v", err)
+    }
+
+    // cfmakeraw와 동일한 설정
+    raw := *oldState
+    raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+    raw.Oflag &^= unix.OPOST
+    raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+    raw.Cflag &^= unix.CSIZE | unix.PARENB
+    raw.Cflag |= unix.CS8
+    raw.Cc[unix.VMIN] = 1
+    raw.Cc[unix.VTIME] = 0
+    if err := unix.IoctlSetTermios(fd, unix.TCSETS, &raw); err != nil {
+        return nil, fmt.Errorf("failed to set terminal to raw mode: %v", err)
+    }
+
+    return func() {
+        unix.IoctlSetTermios(fd, unix.TCSETS, oldState)
+    }, nil
+}
+
+// 터미널 창 크기 복사
+func copyWinsize(from, to *os.File) error {
+    ws, err := unix.IoctlGetWinsize(int(from.Fd()), unix.TIOCGWINSZ)
+    if err != nil {
+        return err
+    }
+    return unix.IoctlSetWinsize(int(to.Fd()), unix.TIOCSWINSZ, ws)
+}
+
+// SIGWINCH를 받을 때마다 호스트 터미널 크기를 PTY에 반영, 반환된 함수로 중지
+func forwardWinsize(from, to *os.File) func() {
+    copyWinsize(from, to)
+
+    sigCh := make(chan os.Signal, 1)
+    signal.Notify(sigCh, syscall.SIGWINCH)
+    go func() {
+        for range sigCh {
+            copyWinsize(from, to)
+        }
+    }()
+
+    return func() {
+        signal.Stop(sigCh)
+        close(sigCh)
+    }
+}
+
+// PTY master fd를 유닉스 소켓으로 전달 (컨테이너 안 devpts에서 할당한 콘솔을 부모에게 넘김)
+func sendConsole(socket *os.File, master *os.File) error {
+    rights := unix.UnixRights(int(master.Fd()))
+    if err := unix.Sendmsg(int(socket.Fd()), []byte{0}, rights, nil, 0); err != nil {
+        return fmt.Errorf("failed to send console: %v", err)
+    }
+    return nil
+}
+
+// sendConsole로 보낸 PTY master 수신
+func recvConsole(socket *os.File) (*os.File, error) {
+    buf := make([]byte, 1)
+    oob := make([]byte, unix.CmsgSpace(4))
+    _, oobn, _, _, err := unix.Recvmsg(int(socket.Fd()), buf, oob, 0)
+    if err != nil {
+        return nil, fmt.Errorf("failed to receive console: %v", err)
+    }
+
+    msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+    if err != nil || len(msgs) == 0 {
+        return nil, fmt.Errorf("container did not send a console")
+    }
+    fds, err := unix.ParseUnixRights(&msgs[0])
+    if err != nil || len(fds) == 0 {
+        return nil, fmt.Errorf("container did not send a console")
+    }
+    return os.NewFile(uintptr(fds[0]), "console"), nil
+}
+
+// 새 세션의 제어 터미널로 PTY slave를 지정하고 표준 입출력에 연결
+func setControllingTerminal(slave *os.File) error {
+    if err := unix.IoctlSetInt(int(slave.Fd()), unix.TIOCSCTTY, 0); err != nil {
+        return fmt.Errorf("failed to set controlling terminal: %v", err)
+    }
+    for fd := 0; fd <= 2; fd++ {
+        if err := unix.Dup3(int(slave.Fd()), fd, 0); err != nil {
+            return fmt.Errorf("failed to attach console to fd %d: %v", fd, err)
+        }
+    }
+    return nil
+}
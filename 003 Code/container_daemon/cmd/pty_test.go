@@ -0,0 +1,49 @@
+package cmd
+
+import (
+    "io"
+    "os"
+    "testing"
+
+    "golang.org/x/sys/unix"
+)
+
+func TestConsoleSocket(t *testing.T) {
+    fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    parent := os.NewFile(uintptr(fds[0]), "parent")
+    child := os.NewFile(uintptr(fds[1]), "child")
+    defer parent.Close()
+
+    // PTY master 대신 파이프의 쓰기 쪽을 넘겨 받은 fd가 같은 파일인지 확인
+    r, w, err := os.Pipe()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer r.Close()
+    if err := sendConsole(child, w); err != nil {
+        t.Fatal(err)
+    }
+    w.Close()
+    console, err := recvConsole(parent)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := console.Write([]byte("hello")); err != nil {
+        t.Fatal(err)
+    }
+    console.Close()
+    data, err := io.ReadAll(r)
+    if err != nil || string(data) != "hello" {
+        t.Fatalf("read %q, %v through the received console, want \"hello\"", data, err)
+    }
+
+    // fd 없이 연결이 끊기면 (nsinit이 실패한 경우) 오류
+    child.Close()
+    if console, err := recvConsole(parent); err == nil {
+        console.Close()
+        t.Fatalf("recvConsole() after the peer closed succeeded")
+    }
+}
@@ -4,21 +4,36 @@ import (
 	"fmt"
 	"os/exec"
 	"os"
+	"syscall"
+	"time"
 	"github.com/spf13/cobra"
 )
 
+var removeForce bool
+
 // 컨테이너 제거 명령어 정의
 var removeCmd = &cobra.Command{
-	Use:   "remove [ContainerName]",
+	Use:   "remove [Container]",
 	Short: "Remove Container",
 	Args:  cobra.ExactArgs(1), // 1개의 인자를 받도록 설정
 	RunE: func(cmd *cobra.Command, args []string) error {
-		containerName := args[0] // 첫 번째 인자가 컨테이너 이름
+		// 첫 번째 인자는 컨테이너 이름, 전체 ID 또는 ID 접두사
+		containerName, err := resolveContainer(args[0])
+		if err != nil {
+			return err
+		}
+		// -f: 실행 중이면 SIGKILL로 정지한 뒤 제거
+		if running, err := isContainerRunning(containerName); err == nil && running && removeForce {
+			if err := stopContainer(containerName, unixSignalName(syscall.SIGKILL), defaultStopTimeout*time.Second); err != nil {
+				return err
+			}
+		}
 		return CtRemove(containerName) // CtRemove 함수 호출
 	},
 }
 
 func init() {
+	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Kill the container first if it is running")
 	rootCmd.AddCommand(removeCmd)
 }
 
@@ -32,8 +47,26 @@ func CtRemove(containerName string) error {
 		return fmt.Errorf("Container %s does not exist", containerName)
 	}
 
-	// rm -rf 명령어를 사용하여 컨테이너 삭제
-	cmd := exec.Command("rm", "-rf", containerPath)
+	// 실행 중인 컨테이너의 루트와 상태를 지우면 프로세스, cgroup, 네트워크를 다시 찾을 수 없음
+	state, err := loadContainerState(containerName)
+	if err != nil {
+		return err
+	}
+	if state.isActive() {
+		return fmt.Errorf("Container %s is %s, stop it first or use -f", state.Name, state.Status)
+	}
+
+	// 삭제 후에는 상태 파일이 없으므로 이벤트에 쓸 ID와 이름을 먼저 읽음
+	event := lifecycleEvent{Type: "container", Action: "remove", ID: state.ID, Name: state.Name}
+	if event.ID == "" {
+		event.ID = containerName
+	}
+
+	// 비정상 종료(dead)로 남은 프로세스, veth, 네트워크 네임스페이스 링크, cgroup 정리
+	releaseContainer(containerName)
+
+	// rm -rf 명령어를 사용하여 컨테이너 삭제 (다른 파일시스템으로는 넘어가지 않음)
+	cmd := exec.Command("rm", "-rf", "--one-file-system", containerPath)
 	output, err := cmd.CombinedOutput() // 명령 실행 후 출력 및 에러를 함께 캡처
 
 	if err != nil {
@@ -41,6 +74,12 @@ func CtRemove(containerName string) error {
 		return fmt.Errorf("Failed to remove container: %s\nOutput: %s", err, string(output))
 	}
 
+	// 컨테이너 루트 밖에 있는 메타데이터 디렉토리도 삭제
+	if err := os.RemoveAll(containerMetaDir(containerName)); err != nil {
+		return fmt.Errorf("Failed to remove container metadata: %v", err)
+	}
+
+	recordEvent(event)
 	fmt.Printf("Container %s removed successfully\n", containerName)
 	return nil
 }
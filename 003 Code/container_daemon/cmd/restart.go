@@ -0,0 +1,46 @@
+package cmd
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+var restartTimeout int
+
+var restartCmd = &cobra.Command{
+    Use:   "restart [container]",
+    Short: "Restart a container with its last run configuration",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        return restartContainer(containerName, time.Duration(restartTimeout)*time.Second)
+    },
+}
+
+func init() {
+    restartCmd.Flags().IntVarP(&restartTimeout, "time", "t", defaultStopTimeout, "Seconds to wait for the container to stop before killing it")
+    rootCmd.AddCommand(restartCmd)
+}
+
+// 컨테이너를 정지한 뒤 저장된 실행 설정으로 백그라운드에서 다시 시작
+func restartContainer(containerName string, timeout time.Duration) error {
+    // 한 번도 실행하지 않은 컨테이너는 다시 시작할 설정이 없음
+    if _, err := loadContainerConfig(containerName); err != nil {
+        return fmt.Errorf("container %s has no run configuration, start it first", containerName)
+    }
+
+    if err := stopContainer(containerName, "", timeout); err != nil {
+        return err
+    }
+    pid, err := startDetached(containerName)
+    if err != nil {
+        return err
+    }
+    fmt.Printf("Container %s restarted in background with PID %d\n", containerName, pid)
+    return nil
+}
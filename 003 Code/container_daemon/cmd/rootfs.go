@@ -0,0 +1,512 @@
+package cmd
+
+import (
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "syscall"
+
+    "golang.org/x/sys/unix"
+)
+
+// 컨테이너에서 내용을 숨기는 경로 (디렉토리는 빈 tmpfs, 파일은 /dev/null로 덮음)
+var defaultMaskedPaths = []string{
+    "/proc/acpi",
+    "/proc/asound",
+    "/proc/interrupts",
+    "/proc/kcore",
+    "/proc/keys",
+    "/proc/latency_stats",
+    "/proc/sched_debug",
+    "/proc/scsi",
+    "/proc/timer_list",
+    "/proc/timer_stats",
+    "/sys/devices/virtual/powercap",
+    "/sys/firmware",
+}
+
+// 읽기 전용으로 다시 마운트하는 경로 (커널 설정 변경 방지)
+var defaultReadonlyPaths = []string{
+    "/proc/bus",
+    "/proc/fs",
+    "/proc/irq",
+    "/proc/sys",
+    "/proc/sysrq-trigger",
+    "/sys",
+}
+
+// tmpfsMount : 컨테이너에 마운트할 tmpfs (--tmpfs /path[:options])
+type tmpfsMount struct {
+    Target  string  `json:"target"`
+    Flags   uintptr `json:"flags"`
+    Options string  `json:"options,omitempty"` // size=, mode= 등 tmpfs 옵션
+}
+
+// --tmpfs 해석: /path[:size=64m,mode=1777,ro,exec,...] (기본 nosuid,nodev,noexec)
+func parseTmpfsSpec(spec string) (tmpfsMount, error) {
+    target, opts, _ := strings.Cut(spec, ":")
+    if !filepath.IsAbs(target) {
+        return tmpfsMount{}, fmt.Errorf("tmpfs path %q must be absolute", target)
+    }
+
+    mount := tmpfsMount{Target: filepath.Clean(target), Flags: syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC}
+    var data []string
+    for _, opt := range strings.Split(opts, ",") {
+        switch opt {
+        case "":
+        case "ro":
+            mount.Flags |= syscall.MS_RDONLY
+        case "rw":
+            mount.Flags &^= syscall.MS_RDONLY
+        case "exec":
+            mount.Flags &^= syscall.MS_NOEXEC
+        case "noexec":
+            mount.Flags |= syscall.MS_NOEXEC
+        case "suid":
+            mount.Flags &^= syscall.MS_NOSUID
+        case "nosuid":
+            mount.Flags |= syscall.MS_NOSUID
+        case "dev":
+            mount.Flags &^= syscall.MS_NODEV
+        case "nodev":
+            mount.Flags |= syscall.MS_NODEV
+        default:
+            key, _, found := strings.Cut(opt, "=")
+            switch key {
+            case "size", "mode", "uid", "gid", "nr_inodes", "nr_blocks":
+            default:
+                return tmpfsMount{}, fmt.Errorf("invalid tmpfs option %q in %s", opt, spec)
+            }
+            if !found {
+                return tmpfsMount{}, fmt.Errorf("tmpfs option %q requires a value", opt)
+            }
+            data = append(data, opt)
+        }
+    }
+    mount.Options = strings.Join(data, ",")
+    return mount, nil
+}
+
+// 표준 장치 파일 (/dev는 컨테이너마다 새 tmpfs)
+var defaultDevices = []struct {
+    name         string
+    major, minor uint32
+}{
+    {"null", 1, 3},
+    {"zero", 1, 5},
+    {"full", 1, 7},
+    {"random", 1, 8},
+    {"urandom", 1, 9},
+    {"tty", 5, 0},
+}
+
+// /dev의 표준 링크 (대상, 이름)
+var defaultDevLinks = [][2]string{
+    {"pts/ptmx", "ptmx"},
+    {"/proc/self/fd", "fd"},
+    {"/proc/self/fd/0", "stdin"},
+    {"/proc/self/fd/1", "stdout"},
+    {"/proc/self/fd/2", "stderr"},
+    {"/proc/kcore", "core"},
+}
+
+// 컨테이너 루트 구성 후 pivot_root (nsinit이 새 마운트 네임스페이스 안에서 호출)
+// 여기서 만든 마운트는 호스트에 보이지 않고 컨테이너 프로세스가 모두 끝나면 함께 사라짐
+func setupRootfs(config *initConfig) error {
+    root := config.Root
+
+    // 이후의 마운트와 해제가 호스트로 전파되지 않도록 전체 트리를 private으로
+    if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+        return fmt.Errorf("failed to make mounts private: %v", err)
+    }
+    // pivot_root는 새 루트가 마운트 지점이어야 함
+    if err := syscall.Mount(root, root, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+        return fmt.Errorf("failed to bind mount container root: %v", err)
+    }
+    // 이미지, cp, import로 루트에 들어온 장치 파일은 열 수 없게 nodev (번들은 설정을 따름)
+    var rootFlags uintptr
+    if !config.Bundle {
+        rootFlags = syscall.MS_NODEV
+        if err := syscall.Mount("", root, "", syscall.MS_BIND|syscall.MS_REMOUNT|rootFlags, ""); err != nil {
+            return fmt.Errorf("failed to remount container root nodev: %v", err)
+        }
+    }
+
+    if config.Bundle {
+        if err := setupBundleMounts(config); err != nil {
+            return err
+        }
+    } else if err := setupDefaultMounts(config); err != nil {
+        return err
+    }
+
+    for _, path := range config.MaskedPaths {
+        if err := maskPath(root, path); err != nil {
+            return err
+        }
+    }
+    for _, path := range config.ReadonlyPaths {
+        if err := readonlyPath(root, path); err != nil {
+            return err
+        }
+    }
+
+    // 하위 마운트(proc, 볼륨, tmpfs)는 각자의 옵션을 유지하고 루트만 읽기 전용
+    if config.ReadonlyRoot {
+        if err := syscall.Mount("", root, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY|rootFlags, ""); err != nil {
+            return fmt.Errorf("failed to remount root read-only: %v", err)
+        }
+    }
+    if config.RootPropagation != 0 {
+        if err := syscall.Mount("", root, "", config.RootPropagation, ""); err != nil {
+            return fmt.Errorf("failed to set root propagation: %v", err)
+        }
+    }
+
+    // createRuntime 훅은 마운트가 끝나고 pivot_root 하기 전에 실행 (runtime create)
+    if config.Bundle {
+        if err := syncCreateRuntime(config); err != nil {
+            return err
+        }
+    }
+
+    return pivotRoot(root)
+}
+
+// run/start: /proc, /sys, /dev와 볼륨, tmpfs
+func setupDefaultMounts(config *initConfig) error {
+    root := config.Root
+
+    // 새 PID 네임스페이스의 /proc과 새 네트워크 네임스페이스의 /sys
+    systemMounts := []struct {
+        source, target, fstype string
+        flags                  uintptr
+    }{
+        {"proc", "/proc", "proc", syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC},
+        {"sysfs", "/sys", "sysfs", syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC},
+    }
+    for _, m := range systemMounts {
+        target, err := mkdirInRoot(root, m.target)
+        if err != nil {
+            return err
+        }
+        if err := syscall.Mount(m.source, target, m.fstype, m.flags, ""); err != nil {
+            return fmt.Errorf("failed to mount %s: %v", m.target, err)
+        }
+    }
+
+    if err := setupDev(root); err != nil {
+        return err
+    }
+    if err := mountVolumes(config.Volumes); err != nil {
+        return err
+    }
+
+    for _, tmpfs := range config.Tmpfs {
+        mountPoint, err := mkdirInRoot(root, tmpfs.Target)
+        if err != nil {
+            return err
+        }
+        if err := syscall.Mount("tmpfs", mountPoint, "tmpfs", tmpfs.Flags, tmpfs.Options); err != nil {
+            return fmt.Errorf("failed to mount tmpfs on %s: %v", tmpfs.Target, err)
+        }
+    }
+    return nil
+}
+
+// runtime create: 번들 설정의 마운트를 순서대로 적용하고 장치 파일과 sysctl 구성
+func setupBundleMounts(config *initConfig) error {
+    root := config.Root
+    for _, m := range config.Mounts {
+        if err := mountSpecMount(root, m); err != nil {
+            return err
+        }
+    }
+
+    // 설정에 있는 장치를 먼저 만들고, 없는 표준 장치를 추가
+    for _, device := range config.Devices {
+        if err := createSpecDevice(root, device); err != nil {
+            return err
+        }
+    }
+    var mode os.FileMode = 0666
+    for _, device := range defaultDevices {
+        spec := specDevice{Type: "c", Path: "/dev/" + device.name, Major: int64(device.major), Minor: int64(device.minor), FileMode: &mode}
+        if err := createSpecDevice(root, spec); err != nil {
+            return err
+        }
+    }
+    for _, link := range defaultDevLinks {
+        target, err := resolveInRoot(root, "/dev/"+link[1])
+        if err != nil {
+            return err
+        }
+        if _, err := os.Lstat(target); err == nil {
+            continue
+        }
+        if err := os.Symlink(link[0], target); err != nil {
+            return fmt.Errorf("failed to create /dev/%s: %v", link[1], err)
+        }
+    }
+
+    // /proc은 설정의 마운트로 이미 준비되어 있음
+    for key, value := range config.Sysctl {
+        path, err := resolveInRoot(root, "/proc/sys/"+strings.ReplaceAll(key, ".", "/"))
+        if err != nil {
+            return err
+        }
+        if err := os.WriteFile(path, []byte(value), 0644); err != nil {
+            return fmt.Errorf("failed to set sysctl %s: %v", key, err)
+        }
+    }
+    return nil
+}
+
+func mountSpecMount(root string, m specMount) error {
+    flags, propagation, data := parseMountOptions(m.Options)
+    if m.Type == "bind" {
+        flags |= syscall.MS_BIND
+    }
+    target, err := resolveInRoot(root, m.Destination)
+    if err != nil {
+        return fmt.Errorf("failed to resolve %s in container: %v", m.Destination, err)
+    }
+
+    if flags&syscall.MS_BIND != 0 {
+        if err := bindSpecMount(m.Source, target, flags); err != nil {
+            return fmt.Errorf("failed to mount %s: %v", m.Destination, err)
+        }
+    } else {
+        if err := os.MkdirAll(target, 0755); err != nil {
+            return fmt.Errorf("failed to create %s: %v", m.Destination, err)
+        }
+        fstype := m.Type
+        if fstype == "cgroup" {
+            fstype = "cgroup2" // v1 계층은 지원하지 않음
+        }
+        err := syscall.Mount(m.Source, target, fstype, flags, data)
+        // 네트워크 네임스페이스를 소유하지 않은 사용자 네임스페이스에서는 sysfs를 새로 마운트할 수 없음
+        if err == syscall.EPERM && fstype == "sysfs" {
+            err = bindSpecMount("/sys", target, flags|syscall.MS_BIND|syscall.MS_REC|syscall.MS_RDONLY)
+        }
+        if err != nil {
+            return fmt.Errorf("failed to mount %s: %v", m.Destination, err)
+        }
+    }
+
+    if propagation != 0 {
+        if err := syscall.Mount("", target, "", propagation, ""); err != nil {
+            return fmt.Errorf("failed to set propagation of %s: %v", m.Destination, err)
+        }
+    }
+    return nil
+}
+
+// bind 마운트: 원본이 파일이면 빈 파일을 마운트 지점으로 만듦
+// bind는 ro, nosuid 등의 나머지 플래그를 무시하므로 다시 마운트해서 적용
+func bindSpecMount(source, target string, flags uintptr) error {
+    info, err := os.Stat(source)
+    if err != nil {
+        return err
+    }
+    if info.IsDir() {
+        if err := os.MkdirAll(target, 0755); err != nil {
+            return err
+        }
+    } else {
+        if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+            return err
+        }
+        file, err := os.OpenFile(target, os.O_CREATE|os.O_RDONLY, 0644)
+        if err != nil {
+            return err
+        }
+        file.Close()
+    }
+
+    if err := syscall.Mount(source, target, "", flags&(syscall.MS_BIND|syscall.MS_REC), ""); err != nil {
+        return err
+    }
+    if rest := flags &^ (syscall.MS_BIND | syscall.MS_REC | syscall.MS_REMOUNT); rest != 0 {
+        return syscall.Mount("", target, "", rest|syscall.MS_BIND|syscall.MS_REMOUNT, "")
+    }
+    return nil
+}
+
+// 장치 파일 생성 (이미 있으면 그대로 둠)
+// 사용자 네임스페이스에서는 mknod가 거부되므로 호스트의 같은 장치를 bind
+func createSpecDevice(root string, device specDevice) error {
+    target, err := resolveInRoot(root, device.Path)
+    if err != nil {
+        return fmt.Errorf("failed to resolve %s in container: %v", device.Path, err)
+    }
+    if _, err := os.Lstat(target); err == nil {
+        return nil
+    }
+    if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+        return fmt.Errorf("failed to create %s: %v", filepath.Dir(device.Path), err)
+    }
+
+    var kind uint32
+    switch device.Type {
+    case "c", "u":
+        kind = syscall.S_IFCHR
+    case "b":
+        kind = syscall.S_IFBLK
+    case "p":
+        kind = syscall.S_IFIFO
+    default:
+        return fmt.Errorf("unknown device type %q for %s", device.Type, device.Path)
+    }
+    var mode os.FileMode = 0666
+    if device.FileMode != nil {
+        mode = device.FileMode.Perm()
+    }
+
+    err = syscall.Mknod(target, kind|uint32(mode), int(unix.Mkdev(uint32(device.Major), uint32(device.Minor))))
+    if err == syscall.EPERM {
+        return bindSpecMount(device.Path, target, syscall.MS_BIND)
+    }
+    if err != nil {
+        return fmt.Errorf("failed to create %s: %v", device.Path, err)
+    }
+    // mknod는 umask의 영향을 받으므로 권한을 다시 지정
+    if err := os.Chmod(target, mode); err != nil {
+        return fmt.Errorf("failed to chmod %s: %v", device.Path, err)
+    }
+    uid, gid := 0, 0
+    if device.UID != nil {
+        uid = int(*device.UID)
+    }
+    if device.GID != nil {
+        gid = int(*device.GID)
+    }
+    if err := os.Lchown(target, uid, gid); err != nil {
+        return fmt.Errorf("failed to chown %s: %v", device.Path, err)
+    }
+    return nil
+}
+
+// 컨테이너 루트 안의 경로를 안전하게 해석하고 디렉토리가 없으면 생성
+func mkdirInRoot(root, path string) (string, error) {
+    target, err := resolveInRoot(root, path)
+    if err != nil {
+        return "", fmt.Errorf("failed to resolve %s in container: %v", path, err)
+    }
+    if err := os.MkdirAll(target, 0755); err != nil {
+        return "", fmt.Errorf("failed to create %s: %v", path, err)
+    }
+    return target, nil
+}
+
+// /dev를 새 tmpfs로 만들고 장치 파일, devpts, shm, mqueue, 표준 링크를 구성
+func setupDev(root string) error {
+    dev, err := mkdirInRoot(root, "/dev")
+    if err != nil {
+        return err
+    }
+    if err := syscall.Mount("tmpfs", dev, "tmpfs", syscall.MS_NOSUID|syscall.MS_STRICTATIME, "mode=755,size=65536k"); err != nil {
+        return fmt.Errorf("failed to mount /dev: %v", err)
+    }
+
+    for _, device := range defaultDevices {
+        path := filepath.Join(dev, device.name)
+        if err := syscall.Mknod(path, syscall.S_IFCHR|0666, int(unix.Mkdev(device.major, device.minor))); err != nil {
+            return fmt.Errorf("failed to create /dev/%s: %v", device.name, err)
+        }
+        // mknod는 umask의 영향을 받으므로 권한을 다시 지정
+        if err := os.Chmod(path, 0666); err != nil {
+            return fmt.Errorf("failed to chmod /dev/%s: %v", device.name, err)
+        }
+    }
+
+    // 컨테이너 전용 devpts 인스턴스 (호스트의 PTY와 분리)
+    devMounts := []struct {
+        source, target, fstype string
+        flags                  uintptr
+        data                   string
+    }{
+        {"devpts", "pts", "devpts", syscall.MS_NOSUID | syscall.MS_NOEXEC, "newinstance,ptmxmode=0666,mode=0620,gid=5"},
+        {"shm", "shm", "tmpfs", syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC, "mode=1777,size=65536k"},
+        {"mqueue", "mqueue", "mqueue", syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC, ""},
+    }
+    for _, m := range devMounts {
+        target := filepath.Join(dev, m.target)
+        if err := os.Mkdir(target, 0755); err != nil {
+            return fmt.Errorf("failed to create /dev/%s: %v", m.target, err)
+        }
+        if err := syscall.Mount(m.source, target, m.fstype, m.flags, m.data); err != nil {
+            return fmt.Errorf("failed to mount /dev/%s: %v", m.target, err)
+        }
+    }
+
+    for _, link := range defaultDevLinks {
+        if err := os.Symlink(link[0], filepath.Join(dev, link[1])); err != nil {
+            return fmt.Errorf("failed to create /dev/%s: %v", link[1], err)
+        }
+    }
+    return nil
+}
+
+// 컨테이너 루트로 pivot_root 하고 이전 루트(호스트 트리)를 떼어냄
+func pivotRoot(root string) error {
+    if err := os.Chdir(root); err != nil {
+        return fmt.Errorf("failed to change to container root: %v", err)
+    }
+    // 이전 루트를 새 루트 위에 쌓은 뒤 바로 분리하므로 별도의 put_old 디렉토리가 필요 없음
+    if err := unix.PivotRoot(".", "."); err != nil {
+        return fmt.Errorf("failed to pivot_root: %v", err)
+    }
+    if err := unix.Unmount(".", unix.MNT_DETACH); err != nil {
+        return fmt.Errorf("failed to detach old root: %v", err)
+    }
+    if err := os.Chdir("/"); err != nil {
+        return fmt.Errorf("failed to change to new root: %v", err)
+    }
+    return nil
+}
+
+func maskPath(root, path string) error {
+    target, err := resolveInRoot(root, path)
+    if err != nil {
+        return err
+    }
+    info, err := os.Stat(target)
+    if os.IsNotExist(err) {
+        return nil // 이 커널에 없는 경로
+    }
+    if err != nil {
+        return fmt.Errorf("failed to stat %s: %v", path, err)
+    }
+
+    if info.IsDir() {
+        err = syscall.Mount("tmpfs", target, "tmpfs", syscall.MS_RDONLY, "size=0")
+    } else {
+        err = syscall.Mount("/dev/null", target, "", syscall.MS_BIND, "")
+    }
+    if err != nil {
+        return fmt.Errorf("failed to mask %s: %v", path, err)
+    }
+    return nil
+}
+
+func readonlyPath(root, path string) error {
+    target, err := resolveInRoot(root, path)
+    if err != nil {
+        return err
+    }
+    if _, err := os.Stat(target); os.IsNotExist(err) {
+        return nil
+    }
+
+    // 자기 자신에 bind 한 뒤 읽기 전용으로 다시 마운트
+    if err := syscall.Mount(target, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+        return fmt.Errorf("failed to bind %s: %v", path, err)
+    }
+    flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY | syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC)
+    if err := syscall.Mount("", target, "", flags, ""); err != nil {
+        return fmt.Errorf("failed to remount %s read-only: %v", path, err)
+    }
+    return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+    "os"
+    "path/filepath"
+    "syscall"
+    "testing"
+)
+
+func TestParseTmpfsSpec(t *testing.T) {
+    const defaults = syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC
+
+    tests := []struct {
+        spec    string
+        want    tmpfsMount
+        wantErr bool
+    }{
+        {spec: "/run", want: tmpfsMount{Target: "/run", Flags: defaults}},
+        {spec: "/tmp/", want: tmpfsMount{Target: "/tmp", Flags: defaults}},
+        {spec: "/tmp:size=64m,mode=1777", want: tmpfsMount{Target: "/tmp", Flags: defaults, Options: "size=64m,mode=1777"}},
+        {spec: "/cache:ro", want: tmpfsMount{Target: "/cache", Flags: defaults | syscall.MS_RDONLY}},
+        {spec: "/cache:ro,rw", want: tmpfsMount{Target: "/cache", Flags: defaults}},
+        {spec: "/bin:exec,suid,dev", want: tmpfsMount{Target: "/bin", Flags: 0}},
+        {spec: "/bin:exec,noexec", want: tmpfsMount{Target: "/bin", Flags: defaults}},
+        {spec: "/data:uid=1000,gid=1000,nr_inodes=100,", want: tmpfsMount{Target: "/data", Flags: defaults, Options: "uid=1000,gid=1000,nr_inodes=100"}},
+        {spec: "tmp", wantErr: true},
+        {spec: "", wantErr: true},
+        {spec: "/tmp:size", wantErr: true},
+        {spec: "/tmp:huge=always", wantErr: true},
+        {spec: "/tmp:bind", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.spec, func(t *testing.T) {
+            got, err := parseTmpfsSpec(tt.spec)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parseTmpfsSpec(%q) = %+v, want error", tt.spec, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != tt.want {
+                t.Fatalf("parseTmpfsSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestMkdirInRoot(t *testing.T) {
+    root := t.TempDir()
+    outside := t.TempDir()
+    if err := os.MkdirAll(filepath.Join(root, "usr/lib"), 0755); err != nil {
+        t.Fatal(err)
+    }
+    // 절대 경로 링크와 루트 밖을 가리키는 링크는 모두 컨테이너 루트 안으로 해석
+    if err := os.Symlink("/usr/lib", filepath.Join(root, "lib")); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Symlink(outside, filepath.Join(root, "escape")); err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        path string
+        want string
+    }{
+        {path: "/dev/pts", want: "/dev/pts"},
+        {path: "/lib/modules", want: "/usr/lib/modules"},
+        {path: "/escape/dir", want: outside + "/dir"},
+        {path: "/../../proc", want: "/proc"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.path, func(t *testing.T) {
+            got, err := mkdirInRoot(root, tt.path)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if want := filepath.Join(root, tt.want); got != want {
+                t.Fatalf("mkdirInRoot(%q) = %s, want %s", tt.path, got, want)
+            }
+            if info, err := os.Stat(got); err != nil || !info.IsDir() {
+                t.Fatalf("mkdirInRoot(%q) did not create a directory: %v", tt.path, err)
+            }
+        })
+    }
+    if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+        t.Fatalf("mkdirInRoot created %v outside the root", entries)
+    }
+
+    // 파일 아래에는 디렉토리를 만들 수 없음
+    if err := os.WriteFile(filepath.Join(root, "file"), nil, 0644); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := mkdirInRoot(root, "/file/dir"); err == nil {
+        t.Fatalf("mkdirInRoot() under a regular file succeeded")
+    }
+}
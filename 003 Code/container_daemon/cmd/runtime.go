@@ -0,0 +1,594 @@
+package cmd
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "net"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "regexp"
+    "strconv"
+    "strings"
+    "syscall"
+    "time"
+
+    "github.com/spf13/cobra"
+    "golang.org/x/sys/unix"
+)
+
+// carte runtime: OCI 번들(config.json + rootfs)을 start와 같은 nsinit으로 실행하는 저수준 런타임
+// 상태는 --root 아래 <id>/state.json, create와 start 사이에는 exec.fifo로 컨테이너를 멈춰 둠
+
+const runtimeDefaultCgroupParent = "carte-runtime"
+
+var (
+    runtimeRoot          string
+    runtimeBundle        string
+    runtimeConsoleSocket string
+    runtimePidFile       string
+    runtimeForceDelete   bool
+)
+
+var runtimeIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.+-]*$`)
+
+// runtimeContainer : runtime create가 기록하는 컨테이너 (<root>/<id>/state.json)
+type runtimeContainer struct {
+    OCIVersion   string            `json:"ociVersion"`
+    ID           string            `json:"id"`
+    Bundle       string            `json:"bundle"`
+    Pid          int               `json:"pid"`
+    PidStartTime uint64            `json:"pidStartTime"`
+    CgroupPath   string            `json:"cgroupPath"`
+    Annotations  map[string]string `json:"annotations,omitempty"`
+    Hooks        *specHooks        `json:"hooks,omitempty"`
+    Created      time.Time         `json:"created"`
+}
+
+// ociState : OCI runtime-spec의 상태 형식 (state 출력과 훅의 표준 입력)
+type ociState struct {
+    OCIVersion  string            `json:"ociVersion"`
+    ID          string            `json:"id"`
+    Status      string            `json:"status"`
+    Pid         int               `json:"pid,omitempty"`
+    Bundle      string            `json:"bundle"`
+    Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// runtimeSyncMessage : create 중 부모와 nsinit이 initSyncFd로 주고받는 메시지 (한 줄에 JSON 하나)
+// nsinit: createRuntime -> 부모: continue(상태) 또는 error -> nsinit: ready
+type runtimeSyncMessage struct {
+    Type  string          `json:"type"`
+    State json.RawMessage `json:"state,omitempty"`
+    Error string          `json:"error,omitempty"`
+}
+
+var runtimeCmd = &cobra.Command{
+    Use:   "runtime",
+    Short: "Run OCI bundles with the OCI runtime command line (create, start, state, kill, delete)",
+}
+
+var runtimeCreateCmd = &cobra.Command{
+    Use:   "create [id]",
+    Short: "Create a container from an OCI bundle and wait for start",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        return runtimeCreate(args[0])
+    },
+}
+
+var runtimeStartCmd = &cobra.Command{
+    Use:   "start [id]",
+    Short: "Run the user process of a created container",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        return runtimeStart(args[0])
+    },
+}
+
+var runtimeStateCmd = &cobra.Command{
+    Use:   "state [id]",
+    Short: "Print the state of a container in OCI state JSON",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        c, err := loadRuntimeContainer(args[0])
+        if err != nil {
+            return err
+        }
+        data, err := json.MarshalIndent(c.state(c.status()), "", "  ")
+        if err != nil {
+            return err
+        }
+        fmt.Println(string(data))
+        return nil
+    },
+}
+
+var runtimeKillCmd = &cobra.Command{
+    Use:   "kill [id] [signal]",
+    Short: "Send a signal to the container process (default SIGTERM)",
+    Args:  cobra.RangeArgs(1, 2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        sig := syscall.SIGTERM
+        if len(args) == 2 {
+            var err error
+            if sig, err = parseSignal(args[1]); err != nil {
+                return err
+            }
+        }
+        return runtimeKill(args[0], sig)
+    },
+}
+
+var runtimeDeleteCmd = &cobra.Command{
+    Use:   "delete [id]",
+    Short: "Delete a stopped container and its resources",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        return runtimeDelete(args[0])
+    },
+}
+
+func init() {
+    runtimeCmd.PersistentFlags().StringVar(&runtimeRoot, "root", "/run/carte/runtime", "Directory for container state")
+    runtimeCreateCmd.Flags().StringVarP(&runtimeBundle, "bundle", "b", ".", "Path to the OCI bundle directory")
+    runtimeCreateCmd.Flags().StringVar(&runtimeConsoleSocket, "console-socket", "", "Unix socket that receives the console master when process.terminal is set")
+    runtimeCreateCmd.Flags().StringVar(&runtimePidFile, "pid-file", "", "Write the container process PID to this file")
+    runtimeDeleteCmd.Flags().BoolVarP(&runtimeForceDelete, "force", "f", false, "Kill the container if it is still running")
+    runtimeCmd.AddCommand(runtimeCreateCmd, runtimeStartCmd, runtimeStateCmd, runtimeKillCmd, runtimeDeleteCmd)
+    rootCmd.AddCommand(runtimeCmd)
+}
+
+func runtimeStateDir(id string) string {
+    return filepath.Join(runtimeRoot, id)
+}
+
+func loadRuntimeContainer(id string) (*runtimeContainer, error) {
+    if !runtimeIDPattern.MatchString(id) {
+        return nil, fmt.Errorf("invalid container id %q", id)
+    }
+    data, err := os.ReadFile(filepath.Join(runtimeStateDir(id), "state.json"))
+    if os.IsNotExist(err) {
+        return nil, fmt.Errorf("container %s does not exist", id)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to read state of %s: %v", id, err)
+    }
+    var c runtimeContainer
+    if err := json.Unmarshal(data, &c); err != nil {
+        return nil, fmt.Errorf("failed to parse state of %s: %v", id, err)
+    }
+    return &c, nil
+}
+
+func saveRuntimeContainer(c *runtimeContainer) error {
+    data, err := json.MarshalIndent(c, "", "  ")
+    if err != nil {
+        return err
+    }
+    path := filepath.Join(runtimeStateDir(c.ID), "state.json")
+    if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+        return fmt.Errorf("failed to write state of %s: %v", c.ID, err)
+    }
+    return os.Rename(path+".tmp", path)
+}
+
+// 상태는 기록하지 않고 매번 판단: 프로세스가 없으면 stopped, exec.fifo가 남아 있으면 아직 start 전
+func (c *runtimeContainer) status() string {
+    if !processAlive(c.Pid, c.PidStartTime) {
+        return "stopped"
+    }
+    if _, err := os.Stat(filepath.Join(runtimeStateDir(c.ID), "exec.fifo")); err == nil {
+        return "created"
+    }
+    return "running"
+}
+
+func (c *runtimeContainer) state(status string) ociState {
+    state := ociState{
+        OCIVersion:  c.OCIVersion,
+        ID:          c.ID,
+        Status:      status,
+        Bundle:      c.Bundle,
+        Annotations: c.Annotations,
+    }
+    if status != "stopped" {
+        state.Pid = c.Pid
+    }
+    return state
+}
+
+// linux.cgroupsPath는 cgroupRoot 기준 (없으면 carte-runtime/<id>)
+func runtimeCgroupPath(id, cgroupsPath string) string {
+    if cgroupsPath == "" {
+        return filepath.Join(cgroupRoot, runtimeDefaultCgroupParent, id)
+    }
+    return filepath.Join(cgroupRoot, filepath.Clean("/"+cgroupsPath))
+}
+
+func runtimeCreate(id string) (err error) {
+    if !runtimeIDPattern.MatchString(id) {
+        return fmt.Errorf("invalid container id %q", id)
+    }
+    bundle, err := filepath.Abs(runtimeBundle)
+    if err != nil {
+        return err
+    }
+    spec, err := loadBundleSpec(bundle)
+    if err != nil {
+        return err
+    }
+    config, attr, err := buildRuntimeInit(bundle, spec)
+    if err != nil {
+        return err
+    }
+    if spec.Process.Terminal && runtimeConsoleSocket == "" {
+        return fmt.Errorf("--console-socket is required when process.terminal is true")
+    }
+
+    if err := os.MkdirAll(runtimeRoot, 0711); err != nil {
+        return fmt.Errorf("failed to create %s: %v", runtimeRoot, err)
+    }
+    dir := runtimeStateDir(id)
+    if err := os.Mkdir(dir, 0711); err != nil {
+        if os.IsExist(err) {
+            return fmt.Errorf("container %s already exists", id)
+        }
+        return fmt.Errorf("failed to create %s: %v", dir, err)
+    }
+    defer func() {
+        if err != nil {
+            os.RemoveAll(dir)
+        }
+    }()
+
+    // clone 시점에 cgroup에 넣어 nsinit이 처음부터 제한을 받도록 함
+    cgroupPath := runtimeCgroupPath(id, spec.Linux.CgroupsPath)
+    defer func() {
+        if err != nil {
+            removeCgroupPath(cgroupPath)
+        }
+    }()
+    cgroupDir, err := setupCgroupPath(cgroupPath, specResourcesToContainer(spec.Linux.Resources))
+    if err != nil {
+        return err
+    }
+    defer cgroupDir.Close()
+    attr.UseCgroupFD = true
+    attr.CgroupFD = int(cgroupDir.Fd())
+
+    // nsinit은 start가 읽기 쪽을 열 때까지 이 fifo의 쓰기 쪽 open에서 멈춤
+    fifoPath := filepath.Join(dir, "exec.fifo")
+    if err := unix.Mkfifo(fifoPath, 0600); err != nil {
+        return fmt.Errorf("failed to create exec fifo: %v", err)
+    }
+    // 사용자 네임스페이스의 root가 /proc/self/fd로 다시 열 수 있도록 소유자를 맞춤
+    for _, m := range attr.UidMappings {
+        if m.ContainerID == 0 {
+            os.Chown(fifoPath, m.HostID, -1)
+        }
+    }
+    fifo, err := os.OpenFile(fifoPath, unix.O_PATH|unix.O_CLOEXEC, 0)
+    if err != nil {
+        return fmt.Errorf("failed to open exec fifo: %v", err)
+    }
+    defer fifo.Close()
+
+    fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+    if err != nil {
+        return fmt.Errorf("failed to create sync socket: %v", err)
+    }
+    parentSync := os.NewFile(uintptr(fds[0]), "init-sync")
+    childSync := os.NewFile(uintptr(fds[1]), "init-sync")
+    defer parentSync.Close()
+
+    // 콘솔 소켓은 그대로 nsinit에 넘겨 PTY master를 직접 보내게 함
+    var console *os.File
+    if spec.Process.Terminal {
+        conn, err := net.Dial("unix", runtimeConsoleSocket)
+        if err != nil {
+            childSync.Close()
+            return fmt.Errorf("failed to connect to console socket: %v", err)
+        }
+        console, err = conn.(*net.UnixConn).File()
+        conn.Close()
+        if err != nil {
+            childSync.Close()
+            return fmt.Errorf("failed to connect to console socket: %v", err)
+        }
+        defer console.Close()
+    }
+
+    cmd, err := newInitCommand()
+    if err != nil {
+        childSync.Close()
+        return err
+    }
+    cmd.SysProcAttr = attr
+    if !spec.Process.Terminal {
+        cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+    }
+    cmd.ExtraFiles = []*os.File{console, fifo, childSync} // consoleSocketFd, execFifoFd, initSyncFd
+    err = startInit(cmd, config)
+    childSync.Close()
+    if err != nil {
+        return fmt.Errorf("failed to start container init: %v", err)
+    }
+    defer func() {
+        if err != nil {
+            cmd.Process.Kill()
+            cmd.Wait()
+        }
+    }()
+
+    c := &runtimeContainer{
+        OCIVersion:  spec.OCIVersion,
+        ID:          id,
+        Bundle:      bundle,
+        Pid:         cmd.Process.Pid,
+        CgroupPath:  cgroupPath,
+        Annotations: spec.Annotations,
+        Hooks:       spec.Hooks,
+        Created:     time.Now().UTC(),
+    }
+    c.PidStartTime, _ = processStartTime(c.Pid)
+    if spec.Process.OOMScoreAdj != nil {
+        adj := strconv.Itoa(*spec.Process.OOMScoreAdj)
+        if err := os.WriteFile(fmt.Sprintf("/proc/%d/oom_score_adj", c.Pid), []byte(adj), 0644); err != nil {
+            return fmt.Errorf("failed to set oom_score_adj: %v", err)
+        }
+    }
+
+    if err := syncRuntimeCreate(parentSync, c); err != nil {
+        return err
+    }
+    if err := saveRuntimeContainer(c); err != nil {
+        return err
+    }
+    if runtimePidFile != "" {
+        if err := writeRuntimePidFile(runtimePidFile, c.Pid); err != nil {
+            return err
+        }
+    }
+    // 컨테이너 프로세스는 create가 끝난 뒤에도 계속 실행됨 (종료 상태는 /proc으로 판단)
+    return cmd.Process.Release()
+}
+
+// nsinit이 마운트를 마치면 prestart/createRuntime 훅을 실행하고, 준비 완료를 받을 때까지 대기
+func syncRuntimeCreate(socket *os.File, c *runtimeContainer) error {
+    decoder := json.NewDecoder(socket)
+    encoder := json.NewEncoder(socket)
+    for {
+        var msg runtimeSyncMessage
+        if err := decoder.Decode(&msg); err != nil {
+            if err == io.EOF {
+                return fmt.Errorf("container init exited before the container was created")
+            }
+            return fmt.Errorf("failed to read from container init: %v", err)
+        }
+
+        switch msg.Type {
+        case "createRuntime":
+            state, err := json.Marshal(c.state("creating"))
+            if err != nil {
+                return err
+            }
+            err = runHooks("prestart", c.Hooks.Prestart, state)
+            if err == nil {
+                err = runHooks("createRuntime", c.Hooks.CreateRuntime, state)
+            }
+            if err != nil {
+                encoder.Encode(runtimeSyncMessage{Type: "error", Error: err.Error()})
+                return err
+            }
+            if err := encoder.Encode(runtimeSyncMessage{Type: "continue", State: state}); err != nil {
+                return fmt.Errorf("failed to write to container init: %v", err)
+            }
+        case "ready":
+            return nil
+        default:
+            return fmt.Errorf("unexpected message %q from container init", msg.Type)
+        }
+    }
+}
+
+// 다른 도구가 읽는 중 불완전한 내용을 보지 않도록 임시 파일에 쓰고 rename
+func writeRuntimePidFile(path string, pid int) error {
+    tmp := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+    if err := os.WriteFile(tmp, []byte(strconv.Itoa(pid)), 0644); err != nil {
+        return fmt.Errorf("failed to write pid file: %v", err)
+    }
+    if err := os.Rename(tmp, path); err != nil {
+        os.Remove(tmp)
+        return fmt.Errorf("failed to write pid file: %v", err)
+    }
+    return nil
+}
+
+func runtimeStart(id string) error {
+    c, err := loadRuntimeContainer(id)
+    if err != nil {
+        return err
+    }
+    if status := c.status(); status != "created" {
+        return fmt.Errorf("cannot start container %s in %s state", id, status)
+    }
+
+    // 읽기 쪽을 열면 nsinit의 쓰기 쪽 open이 끝나고 1바이트를 보낸 뒤 exec
+    fifoPath := filepath.Join(runtimeStateDir(id), "exec.fifo")
+    fifo, err := os.OpenFile(fifoPath, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+    if err != nil {
+        return fmt.Errorf("failed to open exec fifo: %v", err)
+    }
+    defer fifo.Close()
+    buf := make([]byte, 1)
+    for {
+        n, _ := syscall.Read(int(fifo.Fd()), buf)
+        if n > 0 {
+            break
+        }
+        if !processAlive(c.Pid, c.PidStartTime) {
+            return fmt.Errorf("container %s exited before start", id)
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+    if err := os.Remove(fifoPath); err != nil {
+        return fmt.Errorf("failed to remove exec fifo: %v", err)
+    }
+
+    // poststart 훅 실패는 컨테이너를 되돌리지 않음
+    state, _ := json.Marshal(c.state("running"))
+    if err := runHooks("poststart", c.Hooks.Poststart, state); err != nil {
+        fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+    }
+    return nil
+}
+
+func runtimeKill(id string, sig syscall.Signal) error {
+    c, err := loadRuntimeContainer(id)
+    if err != nil {
+        return err
+    }
+    if c.status() == "stopped" {
+        return fmt.Errorf("container %s is not running", id)
+    }
+    if err := syscall.Kill(c.Pid, sig); err != nil {
+        return fmt.Errorf("failed to send %v to container %s: %v", sig, id, err)
+    }
+    return nil
+}
+
+// 컨테이너의 남은 프로세스, cgroup, 상태 디렉토리를 정리한 뒤 poststop 훅 실행
+func runtimeDelete(id string) error {
+    c, err := loadRuntimeContainer(id)
+    if err != nil {
+        return err
+    }
+    switch c.status() {
+    case "running":
+        if !runtimeForceDelete {
+            return fmt.Errorf("cannot delete running container %s, kill it first or use --force", id)
+        }
+        syscall.Kill(c.Pid, syscall.SIGKILL)
+    case "created":
+        syscall.Kill(c.Pid, syscall.SIGKILL)
+    }
+
+    if err := killCgroupPath(c.CgroupPath); err != nil {
+        return err
+    }
+    if err := waitCgroupPathEmpty(c.CgroupPath, 5*time.Second); err != nil {
+        return err
+    }
+    if err := removeCgroupPath(c.CgroupPath); err != nil {
+        return err
+    }
+
+    state, _ := json.Marshal(c.state("stopped"))
+    if err := runHooks("poststop", c.Hooks.Poststop, state); err != nil {
+        fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+    }
+    if err := os.RemoveAll(runtimeStateDir(id)); err != nil {
+        return fmt.Errorf("failed to remove state of %s: %v", id, err)
+    }
+    return nil
+}
+
+// 훅마다 상태 JSON을 표준 입력으로 넘겨 순서대로 실행, 하나라도 실패하면 중단
+func runHooks(stage string, hooks []specHook, state []byte) error {
+    for _, hook := range hooks {
+        if err := runHook(hook, state); err != nil {
+            return fmt.Errorf("%s hook %s failed: %v", stage, hook.Path, err)
+        }
+    }
+    return nil
+}
+
+func runHook(hook specHook, state []byte) error {
+    ctx := context.Background()
+    if hook.Timeout != nil && *hook.Timeout > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, time.Duration(*hook.Timeout)*time.Second)
+        defer cancel()
+    }
+
+    cmd := exec.CommandContext(ctx, hook.Path)
+    if len(hook.Args) > 0 {
+        cmd.Args = hook.Args
+    }
+    cmd.Env = append([]string{}, hook.Env...)
+    cmd.Stdin = bytes.NewReader(state)
+    var output bytes.Buffer
+    cmd.Stdout, cmd.Stderr = &output, &output
+    if err := cmd.Run(); err != nil {
+        if ctx.Err() == context.DeadlineExceeded {
+            return fmt.Errorf("timed out after %ds", *hook.Timeout)
+        }
+        return fmt.Errorf("%v: %s", err, strings.TrimSpace(output.String()))
+    }
+    return nil
+}
+
+// 이하 nsinit 쪽 (Bundle 모드)
+
+// 경로로 지정된 네임스페이스에 합류
+func joinNamespace(ns specNamespace) error {
+    file, err := os.Open(ns.Path)
+    if err != nil {
+        return fmt.Errorf("failed to open %s namespace %s: %v", ns.Type, ns.Path, err)
+    }
+    defer file.Close()
+    if err := unix.Setns(int(file.Fd()), int(namespaceFlags[ns.Type])); err != nil {
+        return fmt.Errorf("failed to join %s namespace %s: %v", ns.Type, ns.Path, err)
+    }
+    return nil
+}
+
+// pivot_root 전: 부모가 런타임 훅을 실행하게 한 뒤 createContainer 훅 실행
+func syncCreateRuntime(config *initConfig) error {
+    config.syncSocket = os.NewFile(initSyncFd, "init-sync")
+    socket := config.syncSocket
+    if err := json.NewEncoder(socket).Encode(runtimeSyncMessage{Type: "createRuntime"}); err != nil {
+        return fmt.Errorf("failed to write to runtime: %v", err)
+    }
+    var msg runtimeSyncMessage
+    if err := json.NewDecoder(socket).Decode(&msg); err != nil {
+        return fmt.Errorf("failed to read from runtime: %v", err)
+    }
+    if msg.Type != "continue" {
+        return fmt.Errorf("runtime aborted create: %s", msg.Error)
+    }
+    config.hookState = msg.State
+    if config.Hooks != nil {
+        return runHooks("createContainer", config.Hooks.CreateContainer, config.hookState)
+    }
+    return nil
+}
+
+// 준비 완료를 알리고 runtime start가 exec.fifo를 열 때까지 대기
+func waitRuntimeStart(config *initConfig) error {
+    err := json.NewEncoder(config.syncSocket).Encode(runtimeSyncMessage{Type: "ready"})
+    config.syncSocket.Close()
+    if err != nil {
+        return fmt.Errorf("failed to write to runtime: %v", err)
+    }
+
+    fifo, err := os.OpenFile(fmt.Sprintf("/proc/self/fd/%d", execFifoFd), os.O_WRONLY, 0)
+    if err != nil {
+        return fmt.Errorf("failed to open exec fifo: %v", err)
+    }
+    _, err = fifo.Write([]byte{0})
+    fifo.Close()
+    unix.Close(execFifoFd)
+    if err != nil {
+        return fmt.Errorf("failed to write exec fifo: %v", err)
+    }
+
+    if config.Hooks != nil {
+        return runHooks("startContainer", config.Hooks.StartContainer, config.hookState)
+    }
+    return nil
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+    "os"
+    "os/exec"
+    "path/filepath"
+    "testing"
+)
+
+func TestRunHooks(t *testing.T) {
+    sh, err := exec.LookPath("sh")
+    if err != nil {
+        t.Skip("sh not found")
+    }
+    // 상태 JSON은 표준 입력으로, args[0]은 훅 이름, 환경 변수는 설정한 것만
+    check := specHook{
+        Path: sh,
+        Args: []string{"hook", "-c", `state=$(cat) && [ "$state" = '{"id":"web"}' ] && [ "$0" = hook ] && [ "$HOOK" = 1 ]`},
+        Env:  []string{"HOOK=1"},
+    }
+    if err := runHooks("prestart", []specHook{check, check}, []byte(`{"id":"web"}`)); err != nil {
+        t.Fatalf("runHooks() error = %v", err)
+    }
+
+    // 실패한 훅 뒤의 훅은 실행하지 않음
+    marker := filepath.Join(t.TempDir(), "ran")
+    fail := specHook{Path: sh, Args: []string{"sh", "-c", "echo broken; exit 1"}}
+    next := specHook{Path: sh, Args: []string{"sh", "-c", "echo > " + marker}}
+    err = runHooks("poststart", []specHook{fail, next}, nil)
+    if err == nil {
+        t.Fatalf("runHooks() with a failing hook succeeded")
+    }
+    if want := "poststart hook " + sh + " failed: exit status 1: broken"; err.Error() != want {
+        t.Fatalf("runHooks() error = %q, want %q", err, want)
+    }
+    if _, err := os.Stat(marker); err == nil {
+        t.Fatalf("runHooks() ran a hook after a failure")
+    }
+}
@@ -0,0 +1,440 @@
+package cmd
+
+import (
+    _ "embed"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "runtime"
+    "strconv"
+    "strings"
+    "unsafe"
+
+    "golang.org/x/sys/unix"
+)
+
+const (
+    seccompDefault    = "default"
+    seccompUnconfined = "unconfined"
+)
+
+// 기본 seccomp 프로파일 (Docker 기본 프로파일과 같은 형식)
+//
+//go:embed seccomp_default.json
+var defaultSeccompProfile []byte
+
+// seccompProfile : Docker/OCI seccomp JSON 프로파일
+type seccompProfile struct {
+    DefaultAction   string           `json:"defaultAction"`
+    DefaultErrnoRet *uint32          `json:"defaultErrnoRet,omitempty"`
+    Architectures   []string         `json:"architectures,omitempty"`
+    Syscalls        []seccompSyscall `json:"syscalls"`
+}
+
+type seccompSyscall struct {
+    Name     string        `json:"name,omitempty"` // 예전 형식 (이름 하나)
+    Names    []string      `json:"names,omitempty"`
+    Action   string        `json:"action"`
+    ErrnoRet *uint32       `json:"errnoRet,omitempty"`
+    Args     []seccompArg  `json:"args,omitempty"`
+    Includes seccompFilter `json:"includes,omitempty"`
+    Excludes seccompFilter `json:"excludes,omitempty"`
+}
+
+type seccompArg struct {
+    Index    uint   `json:"index"`
+    Value    uint64 `json:"value"`
+    ValueTwo uint64 `json:"valueTwo,omitempty"`
+    Op       string `json:"op"`
+}
+
+// seccompFilter : 규칙을 적용할 조건 (권한, 아키텍처, 최소 커널 버전)
+type seccompFilter struct {
+    Caps      []string `json:"caps,omitempty"`
+    Arches    []string `json:"arches,omitempty"`
+    MinKernel string   `json:"minKernel,omitempty"`
+}
+
+// struct seccomp_data 오프셋 (인자는 64비트, 리틀 엔디언 기준)
+const (
+    seccompDataNr   = 0
+    seccompDataArch = 4
+    seccompDataArgs = 16
+)
+
+// --security-opt 해석 (현재는 seccomp=<file|unconfined>만 지원)
+// 반환값은 "default", "unconfined" 또는 프로파일 파일의 절대 경로
+func parseSecurityOpts(securityOpts []string) (string, error) {
+    seccomp := seccompDefault
+    for _, opt := range securityOpts {
+        key, value, found := strings.Cut(opt, "=")
+        if !found {
+            key, value, found = strings.Cut(opt, ":")
+        }
+        if !found || value == "" {
+            return "", fmt.Errorf("invalid --security-opt %q, expected key=value", opt)
+        }
+        switch key {
+        case "seccomp":
+            if value == seccompUnconfined {
+                seccomp = seccompUnconfined
+                continue
+            }
+            path, err := filepath.Abs(value)
+            if err != nil {
+                return "", err
+            }
+            seccomp = path
+        default:
+            return "", fmt.Errorf("unsupported --security-opt %q", key)
+        }
+    }
+    return seccomp, nil
+}
+
+// 설정에 따라 프로파일을 읽어 BPF 필터로 컴파일 (unconfined면 nil)
+// caps는 컨테이너가 가진 권한 목록, nil이면 권한 제한이 없는 것으로 보고 모든 caps 조건을 만족
+func loadSeccompFilter(seccomp string, caps []string) ([]unix.SockFilter, error) {
+    if seccomp == seccompUnconfined {
+        return nil, nil
+    }
+
+    data := defaultSeccompProfile
+    if seccomp != "" && seccomp != seccompDefault {
+        var err error
+        data, err = os.ReadFile(seccomp)
+        if err != nil {
+            return nil, fmt.Errorf("failed to read seccomp profile: %v", err)
+        }
+    }
+
+    var profile seccompProfile
+    if err := json.Unmarshal(data, &profile); err != nil {
+        return nil, fmt.Errorf("failed to parse seccomp profile: %v", err)
+    }
+    return compileSeccompProfile(&profile, caps)
+}
+
+// 프로파일을 cBPF 프로그램으로 변환
+// 규칙은 순서대로 검사하며 처음 일치한 규칙의 동작을 반환하고, 일치하는 규칙이 없으면 기본 동작
+func compileSeccompProfile(profile *seccompProfile, caps []string) ([]unix.SockFilter, error) {
+    if len(seccompSyscalls) == 0 {
+        return nil, fmt.Errorf("seccomp filtering is not supported on %s, use --security-opt seccomp=unconfined", runtime.GOARCH)
+    }
+
+    defaultErrno := uint32(unix.EPERM)
+    if profile.DefaultErrnoRet != nil {
+        defaultErrno = *profile.DefaultErrnoRet
+    }
+    defaultAction, err := seccompAction(profile.DefaultAction, &defaultErrno)
+    if err != nil {
+        return nil, err
+    }
+
+    // 다른 아키텍처의 호출(예: x86_64에서 32비트 int 0x80)은 번호 체계가 다르므로 기본 동작
+    prog := []unix.SockFilter{
+        bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArch),
+        bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, uint32(seccompNativeArch), 1, 0),
+        bpfStmt(unix.BPF_RET|unix.BPF_K, defaultAction),
+    }
+    if seccompSyscallLimit != 0 {
+        prog = append(prog,
+            bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNr),
+            bpfJump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, seccompSyscallLimit, 0, 1),
+            bpfStmt(unix.BPF_RET|unix.BPF_K, defaultAction),
+        )
+    }
+
+    for _, rule := range profile.Syscalls {
+        if !seccompRuleApplies(rule, caps) {
+            continue
+        }
+        action, err := seccompAction(rule.Action, rule.ErrnoRet)
+        if err != nil {
+            return nil, err
+        }
+
+        names := rule.Names
+        if rule.Name != "" {
+            names = append([]string{rule.Name}, names...)
+        }
+
+        // 같은 인자 번호에 대한 조건이 여러 개면 각각 별도 규칙 (Docker와 같은 해석)
+        argSets := [][]seccompArg{rule.Args}
+        if hasDuplicateArgIndex(rule.Args) {
+            argSets = nil
+            for _, arg := range rule.Args {
+                argSets = append(argSets, []seccompArg{arg})
+            }
+        }
+
+        for _, name := range names {
+            nr, ok := seccompSyscalls[name]
+            if !ok {
+                continue // 이 아키텍처에 없는 시스템 콜
+            }
+            for _, args := range argSets {
+                block, err := compileSeccompRule(nr, args, action)
+                if err != nil {
+                    return nil, fmt.Errorf("seccomp rule for %s: %v", name, err)
+                }
+                prog = append(prog, block...)
+            }
+        }
+    }
+
+    prog = append(prog, bpfStmt(unix.BPF_RET|unix.BPF_K, defaultAction))
+    if len(prog) > 4096 {
+        return nil, fmt.Errorf("seccomp profile too large (%d instructions)", len(prog))
+    }
+    return prog, nil
+}
+
+// Docker 동작 이름을 SECCOMP_RET_* 값으로 변환
+func seccompAction(action string, errnoRet *uint32) (uint32, error) {
+    errno := uint32(unix.EPERM)
+    if errnoRet != nil {
+        errno = *errnoRet
+    }
+
+    switch action {
+    case "SCMP_ACT_ALLOW":
+        return unix.SECCOMP_RET_ALLOW, nil
+    case "SCMP_ACT_ERRNO":
+        return unix.SECCOMP_RET_ERRNO | (errno & unix.SECCOMP_RET_DATA), nil
+    case "SCMP_ACT_LOG":
+        return unix.SECCOMP_RET_LOG, nil
+    case "SCMP_ACT_TRAP":
+        return unix.SECCOMP_RET_TRAP, nil
+    case "SCMP_ACT_TRACE":
+        return unix.SECCOMP_RET_TRACE | (errno & unix.SECCOMP_RET_DATA), nil
+    case "SCMP_ACT_KILL", "SCMP_ACT_KILL_THREAD":
+        return unix.SECCOMP_RET_KILL_THREAD, nil
+    case "SCMP_ACT_KILL_PROCESS":
+        return unix.SECCOMP_RET_KILL_PROCESS, nil
+    }
+    return 0, fmt.Errorf("unsupported seccomp action %q", action)
+}
+
+// includes/excludes 조건 확인
+func seccompRuleApplies(rule seccompSyscall, caps []string) bool {
+    hasCap := func(capName string) bool {
+        if caps == nil {
+            return true
+        }
+        for _, c := range caps {
+            if c == capName {
+                return true
+            }
+        }
+        return false
+    }
+    hasArch := func(arches []string) bool {
+        for _, arch := range arches {
+            if arch == runtime.GOARCH {
+                return true
+            }
+        }
+        return false
+    }
+
+    for _, c := range rule.Includes.Caps {
+        if !hasCap(c) {
+            return false
+        }
+    }
+    if len(rule.Includes.Arches) > 0 && !hasArch(rule.Includes.Arches) {
+        return false
+    }
+    if rule.Includes.MinKernel != "" && !kernelAtLeast(rule.Includes.MinKernel) {
+        return false
+    }
+
+    for _, c := range rule.Excludes.Caps {
+        if hasCap(c) {
+            return false
+        }
+    }
+    if hasArch(rule.Excludes.Arches) {
+        return false
+    }
+    if rule.Excludes.MinKernel != "" && kernelAtLeast(rule.Excludes.MinKernel) {
+        return false
+    }
+    return true
+}
+
+func hasDuplicateArgIndex(args []seccompArg) bool {
+    seen := map[uint]bool{}
+    for _, arg := range args {
+        if seen[arg.Index] {
+            return true
+        }
+        seen[arg.Index] = true
+    }
+    return false
+}
+
+// 실행 중인 커널 버전이 "major.minor" 이상인지 확인
+func kernelAtLeast(version string) bool {
+    var uts unix.Utsname
+    if err := unix.Uname(&uts); err != nil {
+        return false
+    }
+    release := unix.ByteSliceToString(uts.Release[:])
+
+    parse := func(v string) (int, int) {
+        parts := strings.SplitN(v, ".", 3)
+        major, _ := strconv.Atoi(parts[0])
+        minor := 0
+        if len(parts) > 1 {
+            digits := parts[1]
+            if i := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+                digits = digits[:i]
+            }
+            minor, _ = strconv.Atoi(digits)
+        }
+        return major, minor
+    }
+
+    haveMajor, haveMinor := parse(release)
+    wantMajor, wantMinor := parse(version)
+    return haveMajor > wantMajor || (haveMajor == wantMajor && haveMinor >= wantMinor)
+}
+
+// bpfInsn : 컴파일 중인 명령, fail 표시가 있는 점프는 규칙 블록 끝(다음 규칙)으로 보정됨
+type bpfInsn struct {
+    unix.SockFilter
+    jtFail bool
+    jfFail bool
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+    return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+    return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
+
+// 시스템 콜 하나에 대한 규칙 블록: 번호와 모든 인자 조건이 맞으면 action 반환, 아니면 다음 블록으로
+func compileSeccompRule(nr uint32, args []seccompArg, action uint32) ([]unix.SockFilter, error) {
+    block := []bpfInsn{
+        {SockFilter: bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNr)},
+        {SockFilter: bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, nr, 0, 0), jfFail: true},
+    }
+    for _, arg := range args {
+        insns, err := compileSeccompArg(arg)
+        if err != nil {
+            return nil, err
+        }
+        block = append(block, insns...)
+    }
+    block = append(block, bpfInsn{SockFilter: bpfStmt(unix.BPF_RET|unix.BPF_K, action)})
+
+    prog := make([]unix.SockFilter, len(block))
+    for i, insn := range block {
+        offset := len(block) - i - 1
+        if offset > 255 {
+            return nil, fmt.Errorf("too many argument conditions")
+        }
+        if insn.jtFail {
+            insn.Jt = uint8(offset)
+        }
+        if insn.jfFail {
+            insn.Jf = uint8(offset)
+        }
+        prog[i] = insn.SockFilter
+    }
+    return prog, nil
+}
+
+// 64비트 인자 비교를 상위/하위 32비트 비교로 나누어 생성
+func compileSeccompArg(arg seccompArg) ([]bpfInsn, error) {
+    if arg.Index > 5 {
+        return nil, fmt.Errorf("invalid argument index %d", arg.Index)
+    }
+    lowOffset := uint32(seccompDataArgs + 8*arg.Index)
+    highOffset := lowOffset + 4
+    low, high := uint32(arg.Value), uint32(arg.Value>>32)
+
+    load := func(offset uint32) bpfInsn {
+        return bpfInsn{SockFilter: bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, offset)}
+    }
+    jump := func(op uint16, k uint32, jt, jf uint8) bpfInsn {
+        return bpfInsn{SockFilter: bpfJump(unix.BPF_JMP|op|unix.BPF_K, k, jt, jf)}
+    }
+    failIfTrue := func(insn bpfInsn) bpfInsn { insn.jtFail = true; return insn }
+    failIfFalse := func(insn bpfInsn) bpfInsn { insn.jfFail = true; return insn }
+
+    switch arg.Op {
+    case "SCMP_CMP_EQ":
+        return []bpfInsn{
+            load(highOffset), failIfFalse(jump(unix.BPF_JEQ, high, 0, 0)),
+            load(lowOffset), failIfFalse(jump(unix.BPF_JEQ, low, 0, 0)),
+        }, nil
+    case "SCMP_CMP_NE":
+        // 상위가 다르면 통과, 같으면 하위까지 같을 때만 실패
+        return []bpfInsn{
+            load(highOffset), jump(unix.BPF_JEQ, high, 0, 2),
+            load(lowOffset), failIfTrue(jump(unix.BPF_JEQ, low, 0, 0)),
+        }, nil
+    case "SCMP_CMP_MASKED_EQ":
+        // value는 마스크, valueTwo는 마스크 적용 후 기대값
+        mask := arg.Value
+        want := arg.ValueTwo & mask
+        return []bpfInsn{
+            load(highOffset),
+            {SockFilter: bpfStmt(unix.BPF_ALU|unix.BPF_AND|unix.BPF_K, uint32(mask>>32))},
+            failIfFalse(jump(unix.BPF_JEQ, uint32(want>>32), 0, 0)),
+            load(lowOffset),
+            {SockFilter: bpfStmt(unix.BPF_ALU|unix.BPF_AND|unix.BPF_K, uint32(mask))},
+            failIfFalse(jump(unix.BPF_JEQ, uint32(want), 0, 0)),
+        }, nil
+    case "SCMP_CMP_GT", "SCMP_CMP_GE":
+        // 상위가 크면 통과, 같으면 하위 비교, 작으면 실패
+        lowOp := uint16(unix.BPF_JGT)
+        if arg.Op == "SCMP_CMP_GE" {
+            lowOp = unix.BPF_JGE
+        }
+        return []bpfInsn{
+            load(highOffset), jump(unix.BPF_JGT, high, 3, 0),
+            failIfFalse(jump(unix.BPF_JEQ, high, 0, 0)),
+            load(lowOffset), failIfFalse(jump(lowOp, low, 0, 0)),
+        }, nil
+    case "SCMP_CMP_LT", "SCMP_CMP_LE":
+        // 상위가 크면 실패, 작으면 통과, 같으면 하위 비교
+        lowOp := uint16(unix.BPF_JGE)
+        if arg.Op == "SCMP_CMP_LE" {
+            lowOp = unix.BPF_JGT
+        }
+        return []bpfInsn{
+            load(highOffset), failIfTrue(jump(unix.BPF_JGT, high, 0, 0)),
+            jump(unix.BPF_JEQ, high, 0, 2),
+            load(lowOffset), failIfTrue(jump(lowOp, low, 0, 0)),
+        }, nil
+    }
+    return nil, fmt.Errorf("unsupported argument operator %q", arg.Op)
+}
+
+// 현재 프로세스의 모든 스레드에 필터 설치 (exec 이후에도 유지됨)
+// 허용 이외의 동작(거부, 종료 등)은 커널 audit 로그에 기록되도록 FLAG_LOG 사용
+func installSeccompFilter(filter []unix.SockFilter) error {
+    if len(filter) == 0 {
+        return nil
+    }
+    prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+
+    flags := uintptr(unix.SECCOMP_FILTER_FLAG_TSYNC | unix.SECCOMP_FILTER_FLAG_LOG)
+    _, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER, flags, uintptr(unsafe.Pointer(&prog)))
+    if errno == unix.EINVAL {
+        // FLAG_LOG를 지원하지 않는 커널 (4.14 미만)
+        _, _, errno = unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER, unix.SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(&prog)))
+    }
+    runtime.KeepAlive(filter)
+    if errno != 0 {
+        return fmt.Errorf("failed to install seccomp filter: %v", errno)
+    }
+    return nil
+}
@@ -0,0 +1,10 @@
+//go:build !amd64 && !arm64
+
+package cmd
+
+// 시스템 콜 표가 없는 아키텍처에서는 seccomp 필터를 만들 수 없음 (unconfined만 가능)
+const seccompNativeArch = 0
+
+const seccompSyscallLimit = 0
+
+var seccompSyscalls = map[string]uint32{}
@@ -0,0 +1,268 @@
+package cmd
+
+import (
+    "encoding/binary"
+    "testing"
+
+    "golang.org/x/sys/unix"
+)
+
+// 커널이 seccomp 필터를 실행하는 방식대로 cBPF 프로그램을 실행하는 테스트용 인터프리터
+// (컴파일러가 만드는 명령만 지원하고, 프로그램 밖으로 점프하거나 RET 없이 끝나면 실패)
+func runSeccompFilter(t *testing.T, prog []unix.SockFilter, arch uint32, nr uint32, args [6]uint64) uint32 {
+    t.Helper()
+    data := make([]byte, 64) // struct seccomp_data
+    binary.LittleEndian.PutUint32(data[seccompDataNr:], nr)
+    binary.LittleEndian.PutUint32(data[seccompDataArch:], arch)
+    for i, arg := range args {
+        binary.LittleEndian.PutUint64(data[seccompDataArgs+8*i:], arg)
+    }
+
+    var a uint32
+    for pc := 0; pc < len(prog); pc++ {
+        insn := prog[pc]
+        switch insn.Code {
+        case unix.BPF_LD | unix.BPF_W | unix.BPF_ABS:
+            if int(insn.K)+4 > len(data) {
+                t.Fatalf("pc %d: load offset %d out of seccomp_data", pc, insn.K)
+            }
+            a = binary.LittleEndian.Uint32(data[insn.K:])
+        case unix.BPF_ALU | unix.BPF_AND | unix.BPF_K:
+            a &= insn.K
+        case unix.BPF_RET | unix.BPF_K:
+            return insn.K
+        case unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, unix.BPF_JMP | unix.BPF_JGT | unix.BPF_K, unix.BPF_JMP | unix.BPF_JGE | unix.BPF_K:
+            var taken bool
+            switch insn.Code &^ (unix.BPF_JMP | unix.BPF_K) {
+            case unix.BPF_JEQ:
+                taken = a == insn.K
+            case unix.BPF_JGT:
+                taken = a > insn.K
+            case unix.BPF_JGE:
+                taken = a >= insn.K
+            }
+            if taken {
+                pc += int(insn.Jt)
+            } else {
+                pc += int(insn.Jf)
+            }
+            if pc+1 >= len(prog) {
+                t.Fatalf("pc %d: jump past the end of the program (%d instructions)", pc, len(prog))
+            }
+        default:
+            t.Fatalf("pc %d: unexpected instruction code %#x", pc, insn.Code)
+        }
+    }
+    t.Fatalf("program ended without RET")
+    return 0
+}
+
+func requireSeccompSupport(t *testing.T) {
+    t.Helper()
+    if len(seccompSyscalls) == 0 {
+        t.Skip("no seccomp syscall table for this architecture")
+    }
+}
+
+func uint32p(v uint32) *uint32 { return &v }
+
+const testErrno = unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM)
+
+func TestCompileSeccompProfileDispatch(t *testing.T) {
+    requireSeccompSupport(t)
+    profile := &seccompProfile{
+        DefaultAction: "SCMP_ACT_ERRNO",
+        Syscalls: []seccompSyscall{
+            {Names: []string{"read", "write"}, Action: "SCMP_ACT_ALLOW"},
+            {Name: "close", Action: "SCMP_ACT_ERRNO", ErrnoRet: uint32p(uint32(unix.EBADF))},
+            // 같은 시스템 콜의 뒤 규칙은 앞 규칙이 맞으면 적용되지 않음
+            {Names: []string{"read"}, Action: "SCMP_ACT_KILL_PROCESS"},
+            {Names: []string{"no_such_syscall"}, Action: "SCMP_ACT_ALLOW"},
+        },
+    }
+    prog, err := compileSeccompProfile(profile, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name string
+        arch uint32
+        nr   uint32
+        want uint32
+    }{
+        {"first rule", seccompNativeArch, seccompSyscalls["read"], unix.SECCOMP_RET_ALLOW},
+        {"second name of first rule", seccompNativeArch, seccompSyscalls["write"], unix.SECCOMP_RET_ALLOW},
+        {"errno from rule", seccompNativeArch, seccompSyscalls["close"], unix.SECCOMP_RET_ERRNO | uint32(unix.EBADF)},
+        {"no matching rule", seccompNativeArch, seccompSyscalls["mount"], testErrno},
+        {"foreign architecture", seccompNativeArch ^ 1, seccompSyscalls["read"], testErrno},
+    }
+    if seccompSyscallLimit != 0 {
+        tests = append(tests, struct {
+            name string
+            arch uint32
+            nr   uint32
+            want uint32
+        }{"syscall number above limit", seccompNativeArch, seccompSyscallLimit | seccompSyscalls["read"], testErrno})
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := runSeccompFilter(t, prog, tt.arch, tt.nr, [6]uint64{}); got != tt.want {
+                t.Fatalf("action = %#x, want %#x", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestCompileSeccompArgComparisons(t *testing.T) {
+    requireSeccompSupport(t)
+    const high = uint64(1) << 32
+
+    tests := []struct {
+        name  string
+        arg   seccompArg
+        value uint64
+        match bool
+    }{
+        {"eq equal", seccompArg{Index: 0, Value: 5, Op: "SCMP_CMP_EQ"}, 5, true},
+        {"eq differs in high word", seccompArg{Index: 0, Value: 5, Op: "SCMP_CMP_EQ"}, high | 5, false},
+        {"eq differs in low word", seccompArg{Index: 0, Value: high | 5, Op: "SCMP_CMP_EQ"}, high | 6, false},
+        {"ne equal", seccompArg{Index: 1, Value: high | 5, Op: "SCMP_CMP_NE"}, high | 5, false},
+        {"ne differs in high word", seccompArg{Index: 1, Value: 5, Op: "SCMP_CMP_NE"}, high | 5, true},
+        {"ne high word equals low value", seccompArg{Index: 1, Value: 1, Op: "SCMP_CMP_NE"}, high | 1, true},
+        {"ne differs in low word", seccompArg{Index: 1, Value: 5, Op: "SCMP_CMP_NE"}, 6, true},
+        {"gt greater in low word", seccompArg{Index: 2, Value: 5, Op: "SCMP_CMP_GT"}, 6, true},
+        {"gt equal", seccompArg{Index: 2, Value: 5, Op: "SCMP_CMP_GT"}, 5, false},
+        {"gt greater in high word", seccompArg{Index: 2, Value: 0xffffffff, Op: "SCMP_CMP_GT"}, high, true},
+        {"gt smaller in high word", seccompArg{Index: 2, Value: high, Op: "SCMP_CMP_GT"}, 0xffffffff, false},
+        {"gt greater in high word smaller in low word", seccompArg{Index: 2, Value: 5, Op: "SCMP_CMP_GT"}, high | 1, true},
+        {"ge equal", seccompArg{Index: 3, Value: high | 5, Op: "SCMP_CMP_GE"}, high | 5, true},
+        {"ge smaller", seccompArg{Index: 3, Value: high | 5, Op: "SCMP_CMP_GE"}, high | 4, false},
+        {"lt smaller in low word", seccompArg{Index: 4, Value: 5, Op: "SCMP_CMP_LT"}, 4, true},
+        {"lt equal", seccompArg{Index: 4, Value: 5, Op: "SCMP_CMP_LT"}, 5, false},
+        {"lt greater in high word", seccompArg{Index: 4, Value: 5, Op: "SCMP_CMP_LT"}, high, false},
+        {"lt smaller in high word", seccompArg{Index: 4, Value: high, Op: "SCMP_CMP_LT"}, 0xffffffff, true},
+        {"lt smaller in high word greater in low word", seccompArg{Index: 4, Value: high | 1, Op: "SCMP_CMP_LT"}, 5, true},
+        {"le equal", seccompArg{Index: 5, Value: high | 5, Op: "SCMP_CMP_LE"}, high | 5, true},
+        {"le greater", seccompArg{Index: 5, Value: high | 5, Op: "SCMP_CMP_LE"}, high | 6, false},
+        {"masked eq match", seccompArg{Index: 0, Value: 0xff00, ValueTwo: 0x1200, Op: "SCMP_CMP_MASKED_EQ"}, 0x12ab, true},
+        {"masked eq mismatch", seccompArg{Index: 0, Value: 0xff00, ValueTwo: 0x1200, Op: "SCMP_CMP_MASKED_EQ"}, 0x13ab, false},
+        {"masked eq high word", seccompArg{Index: 0, Value: high | 1, ValueTwo: high, Op: "SCMP_CMP_MASKED_EQ"}, high | 2, true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            profile := &seccompProfile{
+                DefaultAction: "SCMP_ACT_ERRNO",
+                Syscalls: []seccompSyscall{
+                    {Names: []string{"personality"}, Action: "SCMP_ACT_ALLOW", Args: []seccompArg{tt.arg}},
+                    // 인자 조건이 맞지 않으면 다음 규칙 블록으로 넘어가야 함
+                    {Names: []string{"personality"}, Action: "SCMP_ACT_LOG"},
+                },
+            }
+            prog, err := compileSeccompProfile(profile, nil)
+            if err != nil {
+                t.Fatal(err)
+            }
+            var args [6]uint64
+            args[tt.arg.Index] = tt.value
+            want := uint32(unix.SECCOMP_RET_LOG)
+            if tt.match {
+                want = unix.SECCOMP_RET_ALLOW
+            }
+            if got := runSeccompFilter(t, prog, seccompNativeArch, seccompSyscalls["personality"], args); got != want {
+                t.Fatalf("action = %#x, want %#x", got, want)
+            }
+        })
+    }
+}
+
+func TestCompileSeccompMultipleArgs(t *testing.T) {
+    requireSeccompSupport(t)
+    profile := &seccompProfile{
+        DefaultAction: "SCMP_ACT_ERRNO",
+        Syscalls: []seccompSyscall{
+            // 서로 다른 인자 조건은 모두 맞아야 함
+            {Names: []string{"socket"}, Action: "SCMP_ACT_ALLOW", Args: []seccompArg{
+                {Index: 0, Value: unix.AF_INET, Op: "SCMP_CMP_EQ"},
+                {Index: 1, Value: unix.SOCK_STREAM, Op: "SCMP_CMP_EQ"},
+            }},
+            // 같은 인자 번호의 조건은 하나만 맞으면 됨
+            {Names: []string{"personality"}, Action: "SCMP_ACT_ALLOW", Args: []seccompArg{
+                {Index: 0, Value: 0, Op: "SCMP_CMP_EQ"},
+                {Index: 0, Value: 8, Op: "SCMP_CMP_EQ"},
+            }},
+        },
+    }
+    prog, err := compileSeccompProfile(profile, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name string
+        nr   string
+        args [6]uint64
+        want uint32
+    }{
+        {"all args match", "socket", [6]uint64{unix.AF_INET, unix.SOCK_STREAM}, unix.SECCOMP_RET_ALLOW},
+        {"second arg differs", "socket", [6]uint64{unix.AF_INET, unix.SOCK_DGRAM}, testErrno},
+        {"first arg differs", "socket", [6]uint64{unix.AF_INET6, unix.SOCK_STREAM}, testErrno},
+        {"first alternative", "personality", [6]uint64{0}, unix.SECCOMP_RET_ALLOW},
+        {"second alternative", "personality", [6]uint64{8}, unix.SECCOMP_RET_ALLOW},
+        {"no alternative", "personality", [6]uint64{4}, testErrno},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := runSeccompFilter(t, prog, seccompNativeArch, seccompSyscalls[tt.nr], tt.args); got != tt.want {
+                t.Fatalf("action = %#x, want %#x", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestCompileSeccompProfileErrors(t *testing.T) {
+    requireSeccompSupport(t)
+    tests := []struct {
+        name    string
+        profile seccompProfile
+    }{
+        {"unknown default action", seccompProfile{DefaultAction: "SCMP_ACT_NOPE"}},
+        {"unknown rule action", seccompProfile{DefaultAction: "SCMP_ACT_ALLOW", Syscalls: []seccompSyscall{{Names: []string{"read"}, Action: "SCMP_ACT_NOPE"}}}},
+        {"argument index out of range", seccompProfile{DefaultAction: "SCMP_ACT_ALLOW", Syscalls: []seccompSyscall{{Names: []string{"read"}, Action: "SCMP_ACT_ERRNO", Args: []seccompArg{{Index: 6, Op: "SCMP_CMP_EQ"}}}}}},
+        {"unknown operator", seccompProfile{DefaultAction: "SCMP_ACT_ALLOW", Syscalls: []seccompSyscall{{Names: []string{"read"}, Action: "SCMP_ACT_ERRNO", Args: []seccompArg{{Index: 0, Op: "SCMP_CMP_NOPE"}}}}}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := compileSeccompProfile(&tt.profile, nil); err == nil {
+                t.Fatal("expected an error")
+            }
+        })
+    }
+}
+
+func TestDefaultSeccompProfile(t *testing.T) {
+    requireSeccompSupport(t)
+    prog, err := loadSeccompFilter(seccompDefault, defaultCapabilities)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name string
+        nr   string
+        want uint32
+    }{
+        {"read is allowed", "read", unix.SECCOMP_RET_ALLOW},
+        {"mount needs CAP_SYS_ADMIN", "mount", testErrno},
+        {"reboot needs CAP_SYS_BOOT", "reboot", testErrno},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := runSeccompFilter(t, prog, seccompNativeArch, seccompSyscalls[tt.nr], [6]uint64{}); got != tt.want {
+                t.Fatalf("action = %#x, want %#x", got, tt.want)
+            }
+        })
+    }
+}
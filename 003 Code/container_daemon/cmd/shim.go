@@ -0,0 +1,155 @@
+package cmd
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "syscall"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+// shim : 터미널과 분리된 상태로 컨테이너를 실행하고 종료될 때까지 출력을 로그 파일에 기록
+// 'start -d'가 내부적으로 실행하는 명령이므로 도움말에는 표시하지 않음
+var shimCmd = &cobra.Command{
+    Use:    "shim [containerName]",
+    Hidden: true,
+    Args:   cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        return runShim(args[0])
+    },
+}
+
+func init() {
+    rootCmd.AddCommand(shimCmd)
+}
+
+func runShim(containerName string) error {
+    containerPath := "/CarteDaemon/container/" + containerName
+
+    // start가 저장해 둔 실행 설정 사용
+    opts, err := loadContainerConfig(containerName)
+    if err != nil {
+        return err
+    }
+    tty := opts.Process.Terminal
+
+    // 컨테이너보다 먼저 로그 파일을 열어 둠
+    containerLog, err := openContainerLog(containerLogPath(containerName), opts.LogMaxSize, opts.LogMaxFiles)
+    if err != nil {
+        return err
+    }
+    defer containerLog.Close()
+
+    // attach 클라이언트가 접속할 소켓
+    server, err := newAttachServer(containerAttachSocket(containerName), tty)
+    if err != nil {
+        return err
+    }
+    defer server.Close()
+
+    stdout := containerLog.stream("stdout")
+    stderr := containerLog.stream("stderr")
+    stdio := &containerStdio{Tty: tty}
+    outputDone := make(chan struct{})
+
+    if tty {
+        // PTY 모드에서는 stdout/stderr가 하나로 합쳐져 master로 나옴
+        stdio.OnConsole = func(console *os.File) {
+            server.setInput(console, console)
+            go func() {
+                io.Copy(io.MultiWriter(stdout, server), console)
+                console.Close()
+                close(outputDone)
+            }()
+        }
+    } else {
+        stdio.Stdout = io.MultiWriter(stdout, server)
+        stdio.Stderr = io.MultiWriter(stderr, server)
+        close(outputDone)
+
+        if opts.Process.OpenStdin {
+            stdinReader, stdinWriter, err := os.Pipe()
+            if err != nil {
+                return fmt.Errorf("failed to create stdin pipe: %v", err)
+            }
+            defer stdinReader.Close()
+            defer stdinWriter.Close()
+            stdio.Stdin = stdinReader
+            server.setInput(stdinWriter, nil)
+        }
+    }
+
+    err = startContainer(containerPath, containerName, opts, stdio)
+    if tty {
+        // PTY에 남은 출력을 마저 기록
+        select {
+        case <-outputDone:
+        case <-time.After(time.Second):
+        }
+    }
+
+    stdout.Flush()
+    stderr.Flush()
+    return err
+}
+
+// shim 프로세스를 새 세션으로 띄우고 컨테이너 PID가 기록될 때까지 대기
+func startDetached(containerName string) (int, error) {
+    containerPath := "/CarteDaemon/container/" + containerName
+
+    if _, err := os.Stat(containerPath); os.IsNotExist(err) {
+        return 0, fmt.Errorf("container %s does not exist", containerName)
+    }
+    if running, err := isContainerRunning(containerName); err == nil && running {
+        return 0, fmt.Errorf("container %s is already running", containerName)
+    }
+
+    if err := os.MkdirAll(containerMetaDir(containerName), 0700); err != nil {
+        return 0, fmt.Errorf("failed to create container meta directory: %v", err)
+    }
+    // shim 자체의 출력(디버그 메시지 등)은 별도 파일에 남김
+    shimOut, err := os.OpenFile(filepath.Join(containerMetaDir(containerName), "shim.log"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+    if err != nil {
+        return 0, fmt.Errorf("failed to open shim log: %v", err)
+    }
+    defer shimOut.Close()
+
+    self, err := os.Executable()
+    if err != nil {
+        return 0, fmt.Errorf("failed to find carte executable: %v", err)
+    }
+
+    launchedAt := time.Now().UTC()
+    shim := exec.Command(self, "shim", containerName)
+    shim.Stdout, shim.Stderr = shimOut, shimOut
+    shim.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+    if err := shim.Start(); err != nil {
+        return 0, fmt.Errorf("failed to start shim process: %v", err)
+    }
+    shimExited := make(chan struct{})
+    go func() {
+        shim.Wait()
+        close(shimExited)
+    }()
+
+    // 이번 실행의 시작이 상태 파일에 기록될 때까지 대기
+    deadline := time.After(10 * time.Second)
+    for {
+        if state, err := readContainerState(containerName); err == nil && state.Status == statusRunning && !state.StartedAt.Before(launchedAt) {
+            return state.Pid, nil
+        }
+
+        select {
+        case <-shimExited:
+            // shim이 먼저 종료되었으면 실패로 처리
+            return 0, fmt.Errorf("container %s exited during startup, see %s", containerName, shimOut.Name())
+        case <-deadline:
+            return 0, fmt.Errorf("container %s did not start, see %s", containerName, shimOut.Name())
+        case <-time.After(100 * time.Millisecond):
+        }
+    }
+}
@@ -2,159 +2,432 @@ package cmd
 
 import (
     "fmt"
-    "log"
+    "io"
     "os"
     "os/exec"
     "path/filepath"
+    "strconv"
+    "strings"
     "syscall"
     "time"
     "golang.org/x/sys/unix"
     "github.com/spf13/cobra"
 )
 
+var (
+    startDetach      bool
+    startInteractive bool
+    startTTY         bool
+    startDetachKeys  string
+    startLogMaxSize  string
+    startLogMaxFiles int
+    startPublish     []string
+    startVolumes     []string
+    startEnv         []string
+    startEnvFiles    []string
+    startWorkdir     string
+    startUser        string
+    startCPUs        string
+    startMemory      string
+    startMemorySwap  string
+    startPidsLimit   int64
+    startCPUShares   int64
+    startIOWeight    int64
+    startSecurityOpt []string
+    startCapAdd      []string
+    startCapDrop     []string
+    startReadOnly    bool
+    startWithInit    bool
+    startTmpfs       []string
+    startName        string
+    startHostname    string
+    startAddHosts    []string
+    startDNS         []string
+)
+
+// CLI 플래그와 이미지 설정으로 실행 옵션 구성
+func buildStartOptions(containerName string, cmdArgs []string) (*startOptions, error) {
+    image, err := loadContainerImageConfig(containerName)
+    if err != nil {
+        return nil, err
+    }
+
+    process, err := resolveProcessSpec(image, cmdArgs, startEnv, startEnvFiles, startWorkdir, startUser)
+    if err != nil {
+        return nil, err
+    }
+    process.Terminal = startTTY
+    process.OpenStdin = startInteractive
+
+    maxSize, err := parseSize(startLogMaxSize)
+    if err != nil {
+        return nil, fmt.Errorf("invalid --log-max-size: %v", err)
+    }
+
+    resources, err := parseResources(startCPUs, startMemory, startMemorySwap, startPidsLimit, startCPUShares, startIOWeight)
+    if err != nil {
+        return nil, err
+    }
+
+    seccomp, err := parseSecurityOpts(startSecurityOpt)
+    if err != nil {
+        return nil, err
+    }
+    caps, err := resolveCapabilities(startCapAdd, startCapDrop)
+    if err != nil {
+        return nil, err
+    }
+    // 프로파일 오류는 컨테이너를 만들기 전에 알림
+    if _, err := loadSeccompFilter(seccomp, caps); err != nil {
+        return nil, err
+    }
+
+    // 호스트 이름을 지정하지 않으면 짧은 컨테이너 ID를 사용
+    hostname := startHostname
+    if hostname == "" {
+        state, err := loadContainerState(containerName)
+        if err != nil {
+            return nil, err
+        }
+        hostname = shortID(state.ID)
+    } else if err := validateHostname(hostname); err != nil {
+        return nil, err
+    }
+    dns, err := parseDNSServers(startDNS)
+    if err != nil {
+        return nil, err
+    }
+
+    opts := &startOptions{Process: process, Hostname: hostname, DNS: dns, Resources: resources, ReadOnly: startReadOnly, Init: startWithInit, Seccomp: seccomp, Capabilities: caps, LogMaxSize: maxSize, LogMaxFiles: startLogMaxFiles}
+    for _, spec := range startAddHosts {
+        host, err := parseExtraHost(spec)
+        if err != nil {
+            return nil, err
+        }
+        opts.ExtraHosts = append(opts.ExtraHosts, host)
+    }
+    for _, spec := range startPublish {
+        mapping, err := parsePortMapping(spec)
+        if err != nil {
+            return nil, err
+        }
+        opts.Ports = append(opts.Ports, mapping)
+    }
+    if err := checkDuplicatePorts(opts.Ports); err != nil {
+        return nil, err
+    }
+    for _, spec := range startVolumes {
+        mount, err := parseVolumeSpec(spec)
+        if err != nil {
+            return nil, err
+        }
+        opts.Volumes = append(opts.Volumes, mount)
+    }
+    for _, spec := range startTmpfs {
+        mount, err := parseTmpfsSpec(spec)
+        if err != nil {
+            return nil, err
+        }
+        opts.Tmpfs = append(opts.Tmpfs, mount)
+    }
+    return opts, nil
+}
+
+// 컨테이너 프로세스에 연결할 표준 입출력
+// Tty가 true면 컨테이너 안 devpts에서 PTY를 할당하고 master를 OnConsole로 넘김
+type containerStdio struct {
+    Stdin     io.Reader
+    Stdout    io.Writer
+    Stderr    io.Writer
+    Tty       bool
+    OnConsole func(console *os.File)
+}
+
 var startCmd = &cobra.Command{
-    Use:   "start [containerName]",
+    Use:   "start [container] [-- command args...]",
     Short: "Container start",
-    Args:  cobra.ExactArgs(1),
+    Args:  cobra.MinimumNArgs(1),
     RunE: func(cmd *cobra.Command, args []string) error {
-        containerName := args[0]
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
         containerPath := "/CarteDaemon/container/" + containerName
 
-        // Cgroups 설정
-        if err := setupCgroups(containerPath); err != nil {
-            log.Fatal("Failed to setup cgroups:", err)
+        // 스크립트로 만든 컨테이너는 처음 시작할 때 ID와 이름을 발급
+        if err := initContainerState(containerName); err != nil {
+            return err
+        }
+
+        if startName != "" {
+            if err := renameContainer(containerName, startName); err != nil {
+                return err
+            }
+        }
+
+        // 실행 설정을 결정해 저장 (shim과 restart가 이 설정을 읽어 사용)
+        opts, err := buildStartOptions(containerName, args[1:])
+        if err != nil {
+            return err
+        }
+        if err := saveContainerConfig(containerName, opts); err != nil {
+            return err
+        }
+
+        // 백그라운드 실행: shim 프로세스가 컨테이너를 관리하고 출력은 로그 파일에 기록
+        if startDetach {
+            pid, err := startDetached(containerName)
+            if err != nil {
+                return err
+            }
+            fmt.Printf("Container %s started in background with PID %d\n", containerName, pid)
+            return nil
+        }
+
+        // PTY가 필요한 경우 shim으로 실행한 뒤 바로 attach (detach 키로 빠져나올 수 있음)
+        if startTTY {
+            if _, err := startDetached(containerName); err != nil {
+                return err
+            }
+            return attachContainer(containerName, startDetachKeys)
         }
 
         // 컨테이너 실행
         fmt.Println("Attempting to start container...")
-        if err := startContainer(containerPath, containerName); err != nil {
+        stdio := &containerStdio{Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+        if err := startContainer(containerPath, containerName, opts, stdio); err != nil {
             return fmt.Errorf("error starting container: %v", err)
         }
 
-        fmt.Println("컨테이너가 강제 중지 되었습니다. 마운트 해제를 원할 시 'carte stop testContainer'을 입력해주세요")
+        fmt.Printf("컨테이너 %s가 종료되었습니다\n", containerName)
 
         return nil
     },
 }
 
 func init() {
+    startCmd.Flags().BoolVarP(&startDetach, "detach", "d", false, "Run container in background and write its output to the log file")
+    startCmd.Flags().BoolVarP(&startInteractive, "interactive", "i", false, "Keep STDIN open")
+    startCmd.Flags().BoolVarP(&startTTY, "tty", "t", false, "Allocate a pseudo-TTY")
+    startCmd.Flags().StringVar(&startDetachKeys, "detach-keys", defaultDetachKeys, "Key sequence for detaching from the container")
+    startCmd.Flags().StringVar(&startLogMaxSize, "log-max-size", "10m", "Maximum size of the log file before it is rotated")
+    startCmd.Flags().IntVar(&startLogMaxFiles, "log-max-files", defaultLogMaxFiles, "Maximum number of log files to keep")
+    startCmd.Flags().StringVar(&startName, "name", "", "Assign a name to the container")
+    startCmd.Flags().StringVar(&startHostname, "hostname", "", "Container host name (defaults to the short container ID)")
+    startCmd.Flags().StringArrayVar(&startAddHosts, "add-host", nil, "Add a custom host-to-IP mapping (host:ip, ip may be host-gateway)")
+    startCmd.Flags().StringArrayVar(&startDNS, "dns", nil, "Set custom DNS servers")
+    startCmd.Flags().StringArrayVarP(&startPublish, "publish", "p", nil, "Publish a container port to the host ([hostIP:]hostPort:containerPort[/tcp|udp])")
+    startCmd.Flags().StringArrayVarP(&startVolumes, "volume", "v", nil, "Bind mount a host path or named volume (hostPath|name:ctrPath[:ro])")
+    startCmd.Flags().StringArrayVarP(&startEnv, "env", "e", nil, "Set environment variables (KEY=VAL)")
+    startCmd.Flags().StringArrayVar(&startEnvFiles, "env-file", nil, "Read environment variables from a file")
+    startCmd.Flags().StringVarP(&startWorkdir, "workdir", "w", "", "Working directory inside the container")
+    startCmd.Flags().StringVarP(&startUser, "user", "u", "", "User to run the command as (uid[:gid] or name[:group])")
+    startCmd.Flags().StringVar(&startCPUs, "cpus", "", "Number of CPUs (e.g. 0.5)")
+    startCmd.Flags().StringVarP(&startMemory, "memory", "m", "", "Memory limit (e.g. 512m)")
+    startCmd.Flags().StringVar(&startMemorySwap, "memory-swap", "", "Total memory plus swap limit, -1 for unlimited swap")
+    startCmd.Flags().Int64Var(&startPidsLimit, "pids-limit", 0, "Maximum number of processes")
+    startCmd.Flags().Int64Var(&startCPUShares, "cpu-shares", 0, "Relative CPU weight (2-262144)")
+    startCmd.Flags().Int64Var(&startIOWeight, "io-weight", 0, "Relative block IO weight (1-10000)")
+    startCmd.Flags().BoolVar(&startWithInit, "init", false, "Run an init inside the container that forwards signals and reaps processes")
+    startCmd.Flags().BoolVar(&startReadOnly, "read-only", false, "Mount the container's root filesystem as read only")
+    startCmd.Flags().StringArrayVar(&startTmpfs, "tmpfs", nil, "Mount a tmpfs directory (/path[:size=64m,mode=1777,...])")
+    startCmd.Flags().StringArrayVar(&startCapAdd, "cap-add", nil, "Add Linux capabilities (or ALL)")
+    startCmd.Flags().StringArrayVar(&startCapDrop, "cap-drop", nil, "Drop Linux capabilities (or ALL)")
+    startCmd.Flags().StringArrayVar(&startSecurityOpt, "security-opt", nil, "Security options (seccomp=<profile.json|unconfined>)")
     rootCmd.AddCommand(startCmd)
 }
 
-func startContainer(containerPath, containerName string) error {
-    cmd, err := runInNewNamespace(containerPath, "/bin/busybox", []string{"sh"}, containerName)
+func startContainer(containerPath, containerName string, opts *startOptions, stdio *containerStdio) error {
+    // 컨테이너 서브넷 할당
+    network, err := allocateContainerNetwork(containerName, opts.Ports)
     if err != nil {
+        return fmt.Errorf("failed to allocate container network: %v", err)
+    }
+
+    // 컨테이너 cgroup 생성 (자식 프로세스는 clone 시점에 바로 이 cgroup에 들어감)
+    cgroupDir, err := setupCgroups(containerName, opts.Resources)
+    if err != nil {
+        releaseContainer(containerName)
+        return fmt.Errorf("failed to setup cgroups: %v", err)
+    }
+    defer cgroupDir.Close()
+    oomBefore := readOOMKillCount(containerName)
+
+    // /etc/hostname, /etc/hosts, /etc/resolv.conf 생성 (컨테이너에는 읽기 전용으로 bind)
+    etcMounts, err := prepareEtcFiles(containerPath, containerName, opts, network)
+    if err != nil {
+        releaseContainer(containerName)
+        return err
+    }
+
+    cmd, err := runInNewNamespace(containerPath, containerName, opts, etcMounts, cgroupDir, stdio)
+    if err != nil {
+        releaseContainer(containerName)
         return fmt.Errorf("failed to start container in new namespace: %v", err)
     }
 
     // 네트워크 네임스페이스 설정을 cmd.Start() 이후로 이동
     time.Sleep(1000 * time.Millisecond) // 네트워크 네임스페이스 안정화를 위해 지연 추가
-    if err := setupNetworkNamespace(cmd); err != nil {
+    if err := setupNetworkNamespace(cmd, network); err != nil {
+        cmd.Process.Kill()
+        cmd.Wait()
+        releaseContainer(containerName)
         return fmt.Errorf("failed to setup network namespace: %v", err)
     }
+    recordNetworkConnectEvent(containerName, network)
 
     pid := cmd.Process.Pid
-    if err := recordContainerPID(containerName, pid); err != nil {
-        return fmt.Errorf("failed to record PID: %v", err)
+    if err := markContainerRunning(containerName, pid, opts); err != nil {
+        cmd.Process.Kill()
+        cmd.Wait()
+        releaseContainer(containerName)
+        return fmt.Errorf("failed to record container state: %v", err)
     }
+    recordContainerEvent(containerName, "start", map[string]string{"pid": strconv.Itoa(pid)})
 
-    fmt.Printf("Container %s started with PID %d\n", containerName, pid)
-    if err := cmd.Wait(); err != nil {
-        return fmt.Errorf("process finished with error: %v", err)
+    // 공개 포트를 컨테이너 IP로 전달
+    proxy, err := startPortProxies(network.ContainerIP, network.Ports)
+    if err != nil {
+        cmd.Process.Kill()
+        cmd.Wait()
+        releaseContainer(containerName)
+        markContainerExited(containerName, exitCodeFromState(cmd.ProcessState), false)
+        return fmt.Errorf("failed to publish ports: %v", err)
+    }
+    defer proxy.Close()
+
+    fmt.Printf("Container %s started with PID %d (IP %s)\n", containerName, pid, network.ContainerIP)
+    waitErr := cmd.Wait()
+
+    // 종료 코드와 OOM 여부 기록 (cgroup은 재사용되므로 시작 전 카운터와 비교)
+    // 네트워크와 cgroup을 먼저 정리해야 종료 기록을 기다리는 stop/restart가 같은 이름으로 다시 만들 때 겹치지 않음
+    exitCode := exitCodeFromState(cmd.ProcessState)
+    oomKilled := readOOMKillCount(containerName) > oomBefore
+    releaseContainer(containerName)
+    if err := markContainerExited(containerName, exitCode, oomKilled); err != nil {
+        fmt.Printf("Warning: failed to record exit of container %s: %v\n", containerName, err)
+    }
+    if waitErr != nil {
+        return fmt.Errorf("process finished with error: %v", waitErr)
     }
 
     return nil
 }
 
-func runInNewNamespace(containerPath, path string, args []string, containerName string) (*exec.Cmd, error) {
-    fmt.Println("[DEBUG] Starting runInNewNamespace function")
-    fmt.Printf("[DEBUG] Container path: %s\n", containerPath)
-    fmt.Printf("[DEBUG] Command path: %s\n", path)
+func runInNewNamespace(containerPath, containerName string, opts *startOptions, etcMounts []volumeMount, cgroupDir *os.File, stdio *containerStdio) (*exec.Cmd, error) {
+    process := opts.Process
 
-    // 필수 디렉토리 생성
-    os.MkdirAll(filepath.Join(containerPath, "proc"), 0755)
-    os.MkdirAll(filepath.Join(containerPath, "sys"), 0755)
-    os.MkdirAll(filepath.Join(containerPath, "dev"), 0755)
-    fmt.Println("[DEBUG] Created necessary directories in container path")
-
-    // /dev 디렉토리에 장치 파일 생성
-    if err := syscall.Mknod(filepath.Join(containerPath, "dev/null"), syscall.S_IFCHR|0666, int(unix.Mkdev(1, 3))); err != nil && !os.IsExist(err) {
-        return nil, fmt.Errorf("failed to create /dev/null: %v", err)
+    // 바인드 마운트와 볼륨의 마운트 지점 결정 (마운트는 nsinit이 컨테이너 마운트 네임스페이스에서 수행)
+    if err := resolveVolumeMounts(containerPath, containerName, opts.Volumes); err != nil {
+        return nil, err
     }
-    if err := syscall.Mknod(filepath.Join(containerPath, "dev/zero"), syscall.S_IFCHR|0666, int(unix.Mkdev(1, 5))); err != nil && !os.IsExist(err) {
-        return nil, fmt.Errorf("failed to create /dev/zero: %v", err)
+
+    // 실행 파일과 사용자는 컨테이너 루트 기준으로 찾음
+    path, err := lookPathInContainer(containerPath, process.Args[0], process.Env)
+    if err != nil {
+        return nil, err
     }
-    if err := syscall.Mknod(filepath.Join(containerPath, "dev/random"), syscall.S_IFCHR|0666, int(unix.Mkdev(1, 8))); err != nil && !os.IsExist(err) {
-        return nil, fmt.Errorf("failed to create /dev/random: %v", err)
+    uid, gid, home, err := lookupContainerUser(containerPath, process.User)
+    if err != nil {
+        return nil, err
     }
-    if err := syscall.Mknod(filepath.Join(containerPath, "dev/urandom"), syscall.S_IFCHR|0666, int(unix.Mkdev(1, 9))); err != nil && !os.IsExist(err) {
-        return nil, fmt.Errorf("failed to create /dev/urandom: %v", err)
+    env := mergeEnv([]string{"HOME=" + home}, process.Env)
+    if process.Terminal {
+        env = mergeEnv([]string{"TERM=xterm"}, env)
     }
-    fmt.Println("[DEBUG] Created device files in /dev")
 
-    // containerPath를 자신에게 bind mount
-    if err := syscall.Mount(containerPath, containerPath, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
-        return nil, fmt.Errorf("failed to bind mount container path: %v", err)
+    // 작업 디렉토리가 없으면 생성
+    if workdir, err := resolveInRoot(containerPath, process.Cwd); err == nil {
+        os.MkdirAll(workdir, 0755)
     }
-    fmt.Printf("[DEBUG] Successfully bind-mounted container path %s\n", containerPath)
 
-    // containerPath를 private mount로 설정
-    if err := syscall.Mount("", containerPath, "", syscall.MS_PRIVATE, ""); err != nil {
-        return nil, fmt.Errorf("failed to make container path private: %v", err)
+    // seccomp 필터는 부모에서 컴파일하여 nsinit에 전달
+    filter, err := loadSeccompFilter(opts.Seccomp, opts.Capabilities)
+    if err != nil {
+        return nil, err
     }
-    fmt.Println("[DEBUG] Set container path as private mount")
 
-    // /proc, /sys 마운트
-    if err := syscall.Mount("proc", filepath.Join(containerPath, "proc"), "proc", 0, ""); err != nil {
-        return nil, fmt.Errorf("failed to mount /proc: %v", err)
+    cmd, err := newInitCommand()
+    if err != nil {
+        return nil, err
     }
-    if err := syscall.Mount("sysfs", filepath.Join(containerPath, "sys"), "sysfs", 0, ""); err != nil {
-        return nil, fmt.Errorf("failed to mount /sys: %v", err)
+    cmd.SysProcAttr = &syscall.SysProcAttr{
+        Cloneflags:  syscall.CLONE_NEWUTS | syscall.CLONE_NEWPID | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC,
+        UseCgroupFD: true,
+        CgroupFD:    int(cgroupDir.Fd()),
     }
-    fmt.Println("[DEBUG] Mounted /proc and /sys in container path")
 
-    // 현재 작업 디렉토리를 containerPath로 변경
-    if err := os.Chdir(containerPath); err != nil {
-        return nil, fmt.Errorf("failed to change directory to container path: %v", err)
+    // PTY는 컨테이너의 /dev/pts가 마운트된 뒤 nsinit이 할당해 소켓으로 돌려줌
+    var consoleSocket, childSocket *os.File
+    if stdio.Tty {
+        fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+        if err != nil {
+            return nil, fmt.Errorf("failed to create console socket: %v", err)
+        }
+        consoleSocket = os.NewFile(uintptr(fds[0]), "console-socket")
+        childSocket = os.NewFile(uintptr(fds[1]), "console-socket")
+        defer consoleSocket.Close()
+
+        cmd.ExtraFiles = []*os.File{childSocket}
+        cmd.SysProcAttr.Setsid = true // 제어 터미널을 가질 새 세션
+    } else {
+        cmd.Stdin, cmd.Stdout, cmd.Stderr = stdio.Stdin, stdio.Stdout, stdio.Stderr
     }
-    fmt.Printf("[DEBUG] Changed working directory to container path %s\n", containerPath)
 
-    cmd := exec.Command(path, args...)
-    cmd.SysProcAttr = &syscall.SysProcAttr{
-        Cloneflags: syscall.CLONE_NEWUTS | syscall.CLONE_NEWPID | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET,
-        AmbientCaps: []uintptr{unix.CAP_NET_ADMIN}, // CAP_NET_ADMIN 권한 추가
+    // 루트 구성, 사용자 변경, 권한 축소는 새 네임스페이스 안의 nsinit이 수행 (데몬 자신의 루트와 작업 디렉토리는 그대로)
+    config := &initConfig{
+        Root:          containerPath,
+        Pivot:         true,
+        Hostname:      opts.Hostname,
+        ReadonlyRoot:  opts.ReadOnly,
+        Volumes:       append(etcMounts, opts.Volumes...), // 사용자 볼륨이 /etc 파일을 덮을 수 있도록 나중에 마운트
+        Tmpfs:         opts.Tmpfs,
+        MaskedPaths:   defaultMaskedPaths,
+        ReadonlyPaths: defaultReadonlyPaths,
+        Path:          path,
+        Args:          process.Args,
+        Env:           env,
+        Cwd:           process.Cwd,
+        Uid:           uid,
+        Gid:           gid,
+        Capabilities:  opts.Capabilities,
+        Seccomp:       filter,
+        Console:       stdio.Tty,
+        Init:          opts.Init,
     }
-    cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
-
-    // chroot를 사용하여 루트 디렉토리를 변경
-    fmt.Println("[DEBUG] Attempting chroot...")
-    if err := syscall.Chroot(containerPath); err != nil {
-        return nil, fmt.Errorf("failed to chroot to container path: %v", err)
+    err = startInit(cmd, config)
+    if childSocket != nil {
+        // 부모 쪽 사본을 닫아야 nsinit이 실패했을 때 수신이 끝남
+        childSocket.Close()
     }
-    fmt.Println("[DEBUG] chroot succeeded")
-
-    // 새로운 루트로 이동
-    if err := os.Chdir("/"); err != nil {
-        return nil, fmt.Errorf("failed to change to new root directory after chroot: %v", err)
+    if err != nil {
+        return nil, fmt.Errorf("failed to run command in new namespace: %v", err)
     }
-    fmt.Println("[DEBUG] Changed directory to new root")
 
-    // 프로세스를 시작하여 cmd.Process가 nil이 아닌 상태로 변경
-    if err := cmd.Start(); err != nil {
-        return nil, fmt.Errorf("failed to run command in new namespace: %v", err)
+    if consoleSocket != nil {
+        console, err := recvConsole(consoleSocket)
+        if err != nil {
+            cmd.Process.Kill()
+            cmd.Wait()
+            return nil, err
+        }
+        if stdio.OnConsole != nil {
+            stdio.OnConsole(console)
+        } else {
+            console.Close()
+        }
     }
-    fmt.Println("[DEBUG] Command started successfully")
 
     return cmd, nil
 }
 
 // 컨테이너는 들어가지지만 네트워크 안됨
 // 심볼릭 링크 생성이 안됨(veth[ ls -l /var/run/netns/ ])
-func setupNetworkNamespace(cmd *exec.Cmd) error {
+func setupNetworkNamespace(cmd *exec.Cmd, network *containerNetwork) error {
     pid := cmd.Process.Pid
-    netnsName := fmt.Sprintf("netns_%d", pid)
-    vethHost := fmt.Sprintf("vh_%d", pid)       // 이름을 짧게 수정
-    vethContainer := fmt.Sprintf("vc_%d", pid)  // 이름을 짧게 수정
+    netnsName := network.Netns
+    vethHost := network.HostVeth
+    vethContainer := "vc_" + strings.TrimPrefix(vethHost, "vh_") // 네임스페이스로 옮긴 뒤 eth0으로 변경
 
     // /run/netns 디렉토리가 존재하는지 확인하고, 없으면 생성
     netnsDir := "/run/netns"
@@ -174,14 +447,11 @@ func setupNetworkNamespace(cmd *exec.Cmd) error {
     if err := os.Symlink(netnsPath, netnsLink); err != nil {
         return fmt.Errorf("failed to create netns symlink: %v", err)
     }
-    fmt.Println("[DEBUG] Symbolic link created for netns")
 
     // veth 페어 생성
     if output, err := exec.Command("ip", "link", "add", vethHost, "type", "veth", "peer", "name", vethContainer).CombinedOutput(); err != nil {
         return fmt.Errorf("[ERROR] Failed to create veth pair: %v\nOutput: %s", err, output)
     }
-    fmt.Println("[DEBUG] veth pair created successfully")
-
 
     time.Sleep(100 * time.Millisecond)
 
@@ -189,29 +459,31 @@ func setupNetworkNamespace(cmd *exec.Cmd) error {
     if output, err := exec.Command("ip", "link", "set", vethContainer, "netns", netnsName).CombinedOutput(); err != nil {
         return fmt.Errorf("[ERROR] Failed to move vethContainer to netns: %v\nOutput: %s", err, output)
     }
-    fmt.Println("[DEBUG] vethContainer moved to network namespace")
+
+    if output, err := exec.Command("ip", "netns", "exec", netnsName, "ip", "link", "set", vethContainer, "name", "eth0").CombinedOutput(); err != nil {
+        return fmt.Errorf("[ERROR] Failed to rename vethContainer: %v\nOutput: %s", err, output)
+    }
+    vethContainer = "eth0"
 
     time.Sleep(100 * time.Millisecond)
 
     // 호스트 쪽 vethHost에 IP 주소 할당 및 활성화
-    if output, err := exec.Command("ip", "addr", "add", "192.168.1.1/24", "dev", vethHost).CombinedOutput(); err != nil {
+    if output, err := exec.Command("ip", "addr", "add", network.HostIP+"/24", "dev", vethHost).CombinedOutput(); err != nil {
         return fmt.Errorf("[ERROR] Failed to assign IP to vethHost: %v\nOutput: %s", err, output)
     }
     if output, err := exec.Command("ip", "link", "set", vethHost, "up").CombinedOutput(); err != nil {
         return fmt.Errorf("[ERROR] Failed to bring up vethHost: %v\nOutput: %s", err, output)
     }
-    fmt.Println("[DEBUG] vethHost IP assigned and interface brought up")
 
     time.Sleep(100 * time.Millisecond)
 
     // 네임스페이스 내에서 vethContainer에 IP 주소 할당 및 인터페이스 활성화
-    if output, err := exec.Command("ip", "netns", "exec", netnsName, "ip", "addr", "add", "192.168.1.2/24", "dev", vethContainer).CombinedOutput(); err != nil {
+    if output, err := exec.Command("ip", "netns", "exec", netnsName, "ip", "addr", "add", network.ContainerIP+"/24", "dev", vethContainer).CombinedOutput(); err != nil {
         return fmt.Errorf("[ERROR] Failed to assign IP to vethContainer: %v\nOutput: %s", err, output)
     }
     if output, err := exec.Command("ip", "netns", "exec", netnsName, "ip", "link", "set", vethContainer, "up").CombinedOutput(); err != nil {
         return fmt.Errorf("[ERROR] Failed to bring up vethContainer: %v\nOutput: %s", err, output)
     }
-    fmt.Println("[DEBUG] vethContainer IP assigned and interface brought up")
 
     time.Sleep(100 * time.Millisecond)
 
@@ -219,64 +491,13 @@ func setupNetworkNamespace(cmd *exec.Cmd) error {
     if output, err := exec.Command("ip", "netns", "exec", netnsName, "ip", "link", "set", "lo", "up").CombinedOutput(); err != nil {
         return fmt.Errorf("[ERROR] Failed to bring up loopback in netns: %v\nOutput: %s", err, output)
     }
-    fmt.Println("[DEBUG] Loopback interface brought up in network namespace")
 
     time.Sleep(100 * time.Millisecond)
 
     // 네임스페이스 내 기본 게이트웨이 설정
-    if output, err := exec.Command("ip", "netns", "exec", netnsName, "ip", "route", "add", "default", "via", "192.168.1.1").CombinedOutput(); err != nil {
+    if output, err := exec.Command("ip", "netns", "exec", netnsName, "ip", "route", "add", "default", "via", network.HostIP).CombinedOutput(); err != nil {
         return fmt.Errorf("[ERROR] Failed to set default route in netns: %v\nOutput: %s", err, output)
     }
-    fmt.Println("[DEBUG] Default route set in network namespace")
 
     return nil
 }
-
-
-func recordContainerPID(containerName string, pid int) error {
-    pidFilePath := fmt.Sprintf("pid")
-    pidDir := filepath.Dir(pidFilePath)
-
-    // 디렉토리 생성
-    if err := os.MkdirAll(pidDir, 0755); err != nil {
-        return fmt.Errorf("failed to create PID directory: %v", err)
-    }
-
-    pidFile, err := os.Create(pidFilePath)
-    if err != nil {
-        return fmt.Errorf("failed to create PID file: %v", err)
-    }
-    defer pidFile.Close()
-
-    _, err = pidFile.WriteString(fmt.Sprintf("%d", pid))
-    return err
-}
-
-
-func setupCgroups(containerPath string) error {
-    cgroupRoot := "/CarteDaemon/cgroup"
-    pid := os.Getpid()
-
-    if err := os.MkdirAll(cgroupRoot, 0755); err != nil {
-        return fmt.Errorf("failed to create cgroup path: %v", err)
-    }
-
-    // CPU와 메모리 리소스 제한 설정
-    cpuLimitPath := filepath.Join(cgroupRoot, "cpu", "myContainerGroup")
-    memoryLimitPath := filepath.Join(cgroupRoot, "memory", "myContainerGroup")
-
-    os.MkdirAll(cpuLimitPath, 0755)
-    os.MkdirAll(memoryLimitPath, 0755)
-
-    // CPU 쿼터 설정
-    os.WriteFile(filepath.Join(cpuLimitPath, "cpu.cfs_quota_us"), []byte("100000"), 0644)
-
-    // 메모리 제한 설정 (50MB)
-    os.WriteFile(filepath.Join(memoryLimitPath, "memory.limit_in_bytes"), []byte("52428800"), 0644)
-
-    // 현재 프로세스를 cgroup에 추가
-    os.WriteFile(filepath.Join(cpuLimitPath, "tasks"), []byte(fmt.Sprintf("%d", pid)), 0644)
-    os.WriteFile(filepath.Join(memoryLimitPath, "tasks"), []byte(fmt.Sprintf("%d", pid)), 0644)
-
-    return nil
-}
\ No newline at end of file
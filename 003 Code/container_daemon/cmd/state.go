@@ -0,0 +1,328 @@
+package cmd
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "syscall"
+    "time"
+
+    "golang.org/x/sys/unix"
+)
+
+// 컨테이너 상태
+const (
+    statusCreated = "created"
+    statusRunning = "running"
+    statusPaused  = "paused"
+    statusExited  = "exited"
+    statusDead    = "dead" // 종료 코드를 기록하지 못한 채 프로세스가 사라짐
+)
+
+// containerState : 컨테이너의 현재 상태 (메타데이터 디렉토리의 state.json)
+type containerState struct {
+    ID           string        `json:"id"`
+    Name         string        `json:"name"`
+    Image        string        `json:"image,omitempty"`
+    Status       string        `json:"status"`
+    Pid          int           `json:"pid,omitempty"`
+    PidStartTime uint64        `json:"pidStartTime,omitempty"` // PID 재사용 구분용 (/proc/<pid>/stat의 starttime)
+    ExitCode     int           `json:"exitCode"`
+    OOMKilled    bool          `json:"oomKilled"`
+    CreatedAt    time.Time     `json:"createdAt"`
+    StartedAt    time.Time     `json:"startedAt"`
+    FinishedAt   time.Time     `json:"finishedAt"`
+    Spec         *startOptions `json:"spec,omitempty"` // 마지막 실행에 사용한 설정
+}
+
+func containerStatePath(containerName string) string {
+    return filepath.Join(containerMetaDir(containerName), "state.json")
+}
+
+// 실행 중이거나 일시 정지된 상태 (컨테이너 프로세스가 존재)
+func (s *containerState) isActive() bool {
+    return s.Status == statusRunning || s.Status == statusPaused
+}
+
+// 64자리 16진수 컨테이너 ID
+func newContainerID() (string, error) {
+    buf := make([]byte, 32)
+    if _, err := rand.Read(buf); err != nil {
+        return "", fmt.Errorf("failed to generate container ID: %v", err)
+    }
+    return hex.EncodeToString(buf), nil
+}
+
+// 컨테이너 상태 읽기 (상태 파일을 쓰지 않음)
+// 상태 파일이 아직 없는 컨테이너(스크립트로 만든 루트 등)는 created 상태로 보고하고,
+// 기록상 실행 중인데 프로세스가 없으면 dead로 보고함
+func loadContainerState(containerName string) (*containerState, error) {
+    state, err := readContainerState(containerName)
+    if os.IsNotExist(err) {
+        info, statErr := os.Stat(filepath.Join("/CarteDaemon/container", containerName))
+        if statErr != nil {
+            return nil, fmt.Errorf("container %s does not exist", containerName)
+        }
+        // ID와 이름은 initContainerState가 발급하기 전까지 디렉토리 이름 기준
+        state, err = &containerState{Name: containerName, Status: statusCreated, CreatedAt: info.ModTime().UTC()}, nil
+        if containerIDPattern.MatchString(containerName) {
+            state.ID = containerName
+        }
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    if state.isActive() && !processAlive(state.Pid, state.PidStartTime) {
+        state.Status = statusDead
+    }
+    return state, nil
+}
+
+// 상태 파일이 없으면 created 상태로 새로 기록하고 create 이벤트를 남김 (create_c, start에서 호출)
+func initContainerState(containerName string) error {
+    if _, err := readContainerState(containerName); !os.IsNotExist(err) {
+        return err
+    }
+    if err := updateContainerState(containerName, func(s *containerState) error { return nil }); err != nil {
+        return err
+    }
+    recordContainerEvent(containerName, "create", nil)
+    return nil
+}
+
+func readContainerState(containerName string) (*containerState, error) {
+    data, err := os.ReadFile(containerStatePath(containerName))
+    if err != nil {
+        return nil, err
+    }
+    var state containerState
+    if err := json.Unmarshal(data, &state); err != nil {
+        return nil, fmt.Errorf("failed to parse state of container %s: %v", containerName, err)
+    }
+    return &state, nil
+}
+
+// 상태 파일을 잠근 채로 읽어 수정하고 원자적으로 다시 씀 (shim과 stop 등이 동시에 갱신해도 유실 없음)
+func updateContainerState(containerName string, update func(state *containerState) error) error {
+    containerPath := filepath.Join("/CarteDaemon/container", containerName)
+    info, err := os.Stat(containerPath)
+    if err != nil {
+        return fmt.Errorf("container %s does not exist", containerName)
+    }
+    if err := os.MkdirAll(containerMetaDir(containerName), 0700); err != nil {
+        return fmt.Errorf("failed to create container meta directory: %v", err)
+    }
+
+    lock, err := os.OpenFile(filepath.Join(containerMetaDir(containerName), "state.lock"), os.O_CREATE|os.O_RDWR, 0600)
+    if err != nil {
+        return fmt.Errorf("failed to open state lock: %v", err)
+    }
+    defer lock.Close()
+    if err := unix.Flock(int(lock.Fd()), unix.LOCK_EX); err != nil {
+        return fmt.Errorf("failed to lock container state: %v", err)
+    }
+
+    state, err := readContainerState(containerName)
+    if os.IsNotExist(err) {
+        // 처음 보는 컨테이너: 디렉토리 생성 시각을 생성 시각으로 사용
+        state = &containerState{Status: statusCreated, CreatedAt: info.ModTime().UTC()}
+        if err := assignContainerIdentity(containerName, state); err != nil {
+            return err
+        }
+    } else if err != nil {
+        return err
+    }
+
+    if err := update(state); err != nil {
+        return err
+    }
+
+    data, err := json.MarshalIndent(state, "", "  ")
+    if err != nil {
+        return err
+    }
+    return writeFileAtomic(containerStatePath(containerName), data, 0644)
+}
+
+// 디렉토리 이름이 ID 형식이면 그 ID에 이름을 생성해 붙이고, 아니면 디렉토리 이름을 이름으로 쓰고 ID를 새로 발급
+func assignContainerIdentity(containerName string, state *containerState) error {
+    var err error
+    if containerIDPattern.MatchString(containerName) {
+        state.ID = containerName
+        state.Name, err = generateContainerName(containerName)
+        return err
+    }
+    state.Name = containerName
+    state.ID, err = newContainerID()
+    return err
+}
+
+// 같은 디렉토리의 임시 파일에 쓰고 rename (읽는 쪽은 이전 내용이나 새 내용만 봄)
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+    tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+    if err != nil {
+        return fmt.Errorf("failed to create temporary file for %s: %v", path, err)
+    }
+    defer os.Remove(tmp.Name())
+
+    if _, err := tmp.Write(data); err != nil {
+        tmp.Close()
+        return fmt.Errorf("failed to write %s: %v", path, err)
+    }
+    if err := tmp.Chmod(perm); err != nil {
+        tmp.Close()
+        return fmt.Errorf("failed to chmod %s: %v", path, err)
+    }
+    if err := tmp.Sync(); err != nil {
+        tmp.Close()
+        return fmt.Errorf("failed to sync %s: %v", path, err)
+    }
+    if err := tmp.Close(); err != nil {
+        return fmt.Errorf("failed to close %s: %v", path, err)
+    }
+    if err := os.Rename(tmp.Name(), path); err != nil {
+        return fmt.Errorf("failed to replace %s: %v", path, err)
+    }
+    return nil
+}
+
+// 컨테이너 프로세스 시작 기록
+func markContainerRunning(containerName string, pid int, opts *startOptions) error {
+    startTime, _ := processStartTime(pid)
+    return updateContainerState(containerName, func(s *containerState) error {
+        s.Status = statusRunning
+        s.Pid = pid
+        s.PidStartTime = startTime
+        s.ExitCode = 0
+        s.OOMKilled = false
+        s.StartedAt = time.Now().UTC()
+        s.FinishedAt = time.Time{}
+        s.Spec = opts
+        return nil
+    })
+}
+
+// 컨테이너 프로세스 종료 기록
+func markContainerExited(containerName string, exitCode int, oomKilled bool) error {
+    err := updateContainerState(containerName, func(s *containerState) error {
+        s.Status = statusExited
+        s.Pid = 0
+        s.PidStartTime = 0
+        s.ExitCode = exitCode
+        s.OOMKilled = oomKilled
+        s.FinishedAt = time.Now().UTC()
+        return nil
+    })
+    if err != nil {
+        return err
+    }
+    if oomKilled {
+        recordContainerEvent(containerName, "oom", nil)
+    }
+    recordContainerEvent(containerName, "die", map[string]string{"exitCode": strconv.Itoa(exitCode)})
+    return nil
+}
+
+// 실행 중인 컨테이너의 상태만 변경 (pause/unpause)
+func setContainerStatus(containerName, status string) error {
+    return updateContainerState(containerName, func(s *containerState) error {
+        if !s.isActive() {
+            return fmt.Errorf("container %s is not running", s.Name)
+        }
+        s.Status = status
+        return nil
+    })
+}
+
+// Wait 결과를 종료 코드로 변환 (시그널로 끝나면 128+시그널 번호)
+func exitCodeFromState(ps *os.ProcessState) int {
+    if ps == nil {
+        return -1
+    }
+    if status, ok := ps.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+        return 128 + int(status.Signal())
+    }
+    return ps.ExitCode()
+}
+
+// /proc/<pid>/stat의 ')' 이후 필드 (comm에 공백이나 괄호가 있을 수 있으므로 마지막 ')' 기준)
+func readProcStat(pid int) ([]string, error) {
+    data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+    if err != nil {
+        return nil, err
+    }
+    stat := string(data)
+    fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+    if len(fields) < 20 {
+        return nil, fmt.Errorf("unexpected format of /proc/%d/stat", pid)
+    }
+    return fields, nil
+}
+
+// 부팅 후 프로세스 시작 시각 (stat의 22번째 필드, clock tick 단위)
+func processStartTime(pid int) (uint64, error) {
+    fields, err := readProcStat(pid)
+    if err != nil {
+        return 0, err
+    }
+    return strconv.ParseUint(fields[19], 10, 64)
+}
+
+// PID가 살아 있고 기록한 프로세스와 같은지 확인 (좀비는 종료된 것으로 봄)
+func processAlive(pid int, startTime uint64) bool {
+    if pid <= 0 {
+        return false
+    }
+    fields, err := readProcStat(pid)
+    if err != nil || fields[0] == "Z" || fields[0] == "X" {
+        return false
+    }
+    // 시작 시각을 모르면 PID가 재사용되었을 수 있으므로 같은 프로세스로 보지 않음
+    return startTime != 0 && fields[19] == strconv.FormatUint(startTime, 10)
+}
+
+// cgroup의 memory.events에 기록된 OOM kill 횟수
+func readOOMKillCount(containerName string) uint64 {
+    events, err := readKeyValueFile(filepath.Join(containerCgroupPath(containerName), "memory.events"))
+    if err != nil {
+        return 0
+    }
+    return events["oom_kill"]
+}
+
+// 상태 표시 문자열 (예: "Up 5 minutes", "Exited (0) 2 hours ago")
+func (s *containerState) describe() string {
+    switch s.Status {
+    case statusRunning:
+        return "Up " + humanDuration(time.Since(s.StartedAt))
+    case statusPaused:
+        return "Up " + humanDuration(time.Since(s.StartedAt)) + " (Paused)"
+    case statusExited:
+        desc := fmt.Sprintf("Exited (%d) %s ago", s.ExitCode, humanDuration(time.Since(s.FinishedAt)))
+        if s.OOMKilled {
+            desc += " (OOM killed)"
+        }
+        return desc
+    case statusDead:
+        return "Dead"
+    }
+    return "Created"
+}
+
+func humanDuration(d time.Duration) string {
+    switch {
+    case d < time.Minute:
+        return fmt.Sprintf("%d seconds", int(d.Seconds()))
+    case d < time.Hour:
+        return fmt.Sprintf("%d minutes", int(d.Minutes()))
+    case d < 48*time.Hour:
+        return fmt.Sprintf("%d hours", int(d.Hours()))
+    }
+    return fmt.Sprintf("%d days", int(d.Hours()/24))
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+    "os"
+    "os/exec"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+func TestHumanDuration(t *testing.T) {
+    tests := []struct {
+        d    time.Duration
+        want string
+    }{
+        {d: 0, want: "0 seconds"},
+        {d: 59 * time.Second, want: "59 seconds"},
+        {d: 90 * time.Second, want: "1 minutes"},
+        {d: 3 * time.Hour, want: "3 hours"},
+        {d: 47 * time.Hour, want: "47 hours"},
+        {d: 72 * time.Hour, want: "3 days"},
+    }
+
+    for _, tt := range tests {
+        if got := humanDuration(tt.d); got != tt.want {
+            t.Fatalf("humanDuration(%v) = %q, want %q", tt.d, got, tt.want)
+        }
+    }
+}
+
+func TestContainerStateDescribe(t *testing.T) {
+    hourAgo := time.Now().Add(-time.Hour - time.Second)
+    tests := []struct {
+        state containerState
+        want  string
+    }{
+        {state: containerState{Status: statusCreated}, want: "Created"},
+        {state: containerState{Status: statusRunning, StartedAt: hourAgo}, want: "Up 1 hours"},
+        {state: containerState{Status: statusPaused, StartedAt: hourAgo}, want: "Up 1 hours (Paused)"},
+        {state: containerState{Status: statusExited, ExitCode: 2, FinishedAt: hourAgo}, want: "Exited (2) 1 hours ago"},
+        {state: containerState{Status: statusExited, ExitCode: 137, OOMKilled: true, FinishedAt: hourAgo}, want: "Exited (137) 1 hours ago (OOM killed)"},
+        {state: containerState{Status: statusDead}, want: "Dead"},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.state.Status, func(t *testing.T) {
+            if got := tt.state.describe(); got != tt.want {
+                t.Fatalf("describe() = %q, want %q", got, tt.want)
+            }
+            if active := tt.state.Status == statusRunning || tt.state.Status == statusPaused; tt.state.isActive() != active {
+                t.Fatalf("isActive() = %v for status %s", tt.state.isActive(), tt.state.Status)
+            }
+        })
+    }
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "state.json")
+    if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+        t.Fatal(err)
+    }
+    if err := writeFileAtomic(path, []byte("new"), 0644); err != nil {
+        t.Fatal(err)
+    }
+
+    data, err := os.ReadFile(path)
+    if err != nil || string(data) != "new" {
+        t.Fatalf("read %q, %v after writeFileAtomic(), want \"new\"", data, err)
+    }
+    if info, _ := os.Stat(path); info.Mode().Perm() != 0644 {
+        t.Fatalf("mode = %v, want 0644", info.Mode().Perm())
+    }
+    // 임시 파일이 남지 않아야 함
+    if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+        t.Fatalf("directory has %d entries after writeFileAtomic(), want 1", len(entries))
+    }
+
+    if err := writeFileAtomic(filepath.Join(dir, "missing", "state.json"), nil, 0644); err == nil {
+        t.Fatalf("writeFileAtomic() into a missing directory succeeded")
+    }
+}
+
+func TestProcessAlive(t *testing.T) {
+    pid := os.Getpid()
+    startTime, err := processStartTime(pid)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if !processAlive(pid, startTime) {
+        t.Fatalf("processAlive(self, %d) = false", startTime)
+    }
+    // 시작 시각이 다르거나 모르면 PID가 재사용된 것으로 봄
+    if processAlive(pid, startTime+1) || processAlive(pid, 0) {
+        t.Fatalf("processAlive() matched a different start time")
+    }
+    if processAlive(0, startTime) || processAlive(-1, startTime) {
+        t.Fatalf("processAlive() accepted a non-positive pid")
+    }
+
+    // 종료된 뒤 회수된 프로세스
+    cmd := exec.Command("true")
+    if err := cmd.Run(); err != nil {
+        t.Skipf("cannot run true: %v", err)
+    }
+    if processAlive(cmd.Process.Pid, 1) {
+        t.Fatalf("processAlive() = true for an exited process")
+    }
+}
+
+func TestExitCodeFromState(t *testing.T) {
+    if got := exitCodeFromState(nil); got != -1 {
+        t.Fatalf("exitCodeFromState(nil) = %d, want -1", got)
+    }
+
+    tests := []struct {
+        script string
+        want   int
+    }{
+        {script: "exit 0", want: 0},
+        {script: "exit 3", want: 3},
+        {script: "kill -KILL $$", want: 128 + 9},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.script, func(t *testing.T) {
+            cmd := exec.Command("sh", "-c", tt.script)
+            cmd.Run()
+            if cmd.ProcessState == nil {
+                t.Skip("cannot run sh")
+            }
+            if got := exitCodeFromState(cmd.ProcessState); got != tt.want {
+                t.Fatalf("exitCodeFromState(%q) = %d, want %d", tt.script, got, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "text/tabwriter"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+var (
+    statsNoStream bool
+    statsFormat   string
+)
+
+// containerStats : 한 시점의 컨테이너 자원 사용량
+type containerStats struct {
+    Name        string  `json:"name"`
+    CPUPercent  float64 `json:"cpuPercent"`
+    MemoryUsage uint64  `json:"memoryUsage"`
+    MemoryLimit uint64  `json:"memoryLimit"`
+    MemPercent  float64 `json:"memoryPercent"`
+    NetRx       uint64  `json:"netRx"`
+    NetTx       uint64  `json:"netTx"`
+    BlockRead   uint64  `json:"blockRead"`
+    BlockWrite  uint64  `json:"blockWrite"`
+    Pids        uint64  `json:"pids"`
+
+    cpuUsageUsec uint64
+    readAt       time.Time
+}
+
+var statsCmd = &cobra.Command{
+    Use:   "stats [containerName...]",
+    Short: "Display a live stream of container resource usage",
+    RunE: func(cmd *cobra.Command, args []string) error {
+        if statsFormat != "" && statsFormat != "table" && statsFormat != "json" {
+            return fmt.Errorf("unknown format %q, expected table or json", statsFormat)
+        }
+        return showStats(args, statsNoStream, statsFormat == "json")
+    },
+}
+
+func init() {
+    statsCmd.Flags().BoolVar(&statsNoStream, "no-stream", false, "Print the first result and exit")
+    statsCmd.Flags().StringVar(&statsFormat, "format", "table", "Output format (table or json)")
+    rootCmd.AddCommand(statsCmd)
+}
+
+// 이름을 주지 않으면 실행 중인 모든 컨테이너
+func statsTargets(names []string) ([]string, error) {
+    if len(names) > 0 {
+        var targets []string
+        for _, name := range names {
+            containerName, err := resolveContainer(name)
+            if err != nil {
+                return nil, err
+            }
+            targets = append(targets, containerName)
+        }
+        return targets, nil
+    }
+
+    files, err := os.ReadDir("/CarteDaemon/container")
+    if err != nil {
+        return nil, fmt.Errorf("failed to read container directory: %v", err)
+    }
+    for _, file := range files {
+        if !file.IsDir() {
+            continue
+        }
+        if running, err := isContainerRunning(file.Name()); err == nil && running {
+            names = append(names, file.Name())
+        }
+    }
+    return names, nil
+}
+
+func showStats(names []string, noStream, jsonFormat bool) error {
+    targets, err := statsTargets(names)
+    if err != nil {
+        return err
+    }
+
+    // CPU 사용률은 두 번 읽은 값의 차이로 계산
+    previous := map[string]*containerStats{}
+    for _, name := range targets {
+        if stats, err := readContainerStats(name); err == nil {
+            previous[name] = stats
+        }
+    }
+
+    for {
+        time.Sleep(time.Second)
+
+        var current []*containerStats
+        for _, name := range targets {
+            stats, err := readContainerStats(name)
+            if err != nil {
+                if len(names) > 0 {
+                    return err
+                }
+                continue
+            }
+            if prev, ok := previous[name]; ok {
+                elapsed := stats.readAt.Sub(prev.readAt).Microseconds()
+                if elapsed > 0 && stats.cpuUsageUsec >= prev.cpuUsageUsec {
+                    stats.CPUPercent = float64(stats.cpuUsageUsec-prev.cpuUsageUsec) / float64(elapsed) * 100
+                }
+            }
+            previous[name] = stats
+            current = append(current, stats)
+        }
+
+        if jsonFormat {
+            for _, stats := range current {
+                data, err := json.Marshal(stats)
+                if err != nil {
+                    return err
+                }
+                fmt.Println(string(data))
+            }
+        } else {
+            if !noStream {
+                fmt.Print("\033[2J\033[H") // 화면을 지우고 처음부터 다시 그림
+            }
+            printStatsTable(current)
+        }
+
+        if noStream {
+            return nil
+        }
+
+        // 이름을 주지 않았으면 새로 시작된 컨테이너도 포함
+        if len(names) == 0 {
+            if refreshed, err := statsTargets(nil); err == nil {
+                targets = refreshed
+            }
+        }
+    }
+}
+
+func printStatsTable(stats []*containerStats) {
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+    fmt.Fprintln(w, "NAME\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\tPIDS")
+    for _, s := range stats {
+        fmt.Fprintf(w, "%s\t%.2f%%\t%s / %s\t%.2f%%\t%s / %s\t%s / %s\t%d\n",
+            s.Name, s.CPUPercent,
+            formatBytes(s.MemoryUsage), formatBytes(s.MemoryLimit), s.MemPercent,
+            formatBytes(s.NetRx), formatBytes(s.NetTx),
+            formatBytes(s.BlockRead), formatBytes(s.BlockWrite),
+            s.Pids)
+    }
+    w.Flush()
+}
+
+// cgroup 파일과 veth 통계를 읽어 현재 사용량 계산
+func readContainerStats(containerName string) (*containerStats, error) {
+    cgroupPath := containerCgroupPath(containerName)
+    if _, err := os.Stat(cgroupPath); err != nil {
+        return nil, fmt.Errorf("no cgroup for container %s: %v", containerName, err)
+    }
+
+    stats := &containerStats{Name: containerName, readAt: time.Now()}
+    if state, err := readContainerState(containerName); err == nil {
+        stats.Name = state.Name
+    }
+
+    cpuStat, err := readKeyValueFile(filepath.Join(cgroupPath, "cpu.stat"))
+    if err != nil {
+        return nil, fmt.Errorf("failed to read cpu.stat: %v", err)
+    }
+    stats.cpuUsageUsec = cpuStat["usage_usec"]
+
+    // 페이지 캐시 중 비활성 부분은 사용량에서 제외
+    stats.MemoryUsage, _ = readUintFile(filepath.Join(cgroupPath, "memory.current"))
+    if memStat, err := readKeyValueFile(filepath.Join(cgroupPath, "memory.stat")); err == nil {
+        if inactive := memStat["inactive_file"]; inactive < stats.MemoryUsage {
+            stats.MemoryUsage -= inactive
+        }
+    }
+    stats.MemoryLimit, err = readUintFile(filepath.Join(cgroupPath, "memory.max"))
+    if err != nil || stats.MemoryLimit == 0 {
+        stats.MemoryLimit = hostMemoryTotal()
+    }
+    if stats.MemoryLimit > 0 {
+        stats.MemPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+    }
+
+    stats.Pids, _ = readUintFile(filepath.Join(cgroupPath, "pids.current"))
+    stats.BlockRead, stats.BlockWrite = readIOStat(filepath.Join(cgroupPath, "io.stat"))
+
+    // 호스트 쪽 veth에서 받은 양이 컨테이너가 보낸 양
+    if network, err := loadContainerNetwork(containerName); err == nil && network.HostVeth != "" {
+        netDir := filepath.Join("/sys/class/net", network.HostVeth, "statistics")
+        hostRx, _ := readUintFile(filepath.Join(netDir, "rx_bytes"))
+        hostTx, _ := readUintFile(filepath.Join(netDir, "tx_bytes"))
+        stats.NetRx, stats.NetTx = hostTx, hostRx
+    }
+
+    return stats, nil
+}
+
+// "key value" 형식 파일 읽기 (cpu.stat, memory.stat)
+func readKeyValueFile(path string) (map[string]uint64, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    values := map[string]uint64{}
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        fields := strings.Fields(scanner.Text())
+        if len(fields) != 2 {
+            continue
+        }
+        if n, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+            values[fields[0]] = n
+        }
+    }
+    return values, scanner.Err()
+}
+
+// 숫자 하나만 들어 있는 파일 읽기 ("max"는 0으로 취급)
+func readUintFile(path string) (uint64, error) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return 0, err
+    }
+    value := strings.TrimSpace(string(data))
+    if value == "max" {
+        return 0, nil
+    }
+    return strconv.ParseUint(value, 10, 64)
+}
+
+// io.stat의 장치별 rbytes/wbytes 합계
+func readIOStat(path string) (uint64, uint64) {
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return 0, 0
+    }
+
+    var read, write uint64
+    for _, line := range strings.Split(string(data), "\n") {
+        for _, field := range strings.Fields(line) {
+            key, value, found := strings.Cut(field, "=")
+            if !found {
+                continue
+            }
+            n, err := strconv.ParseUint(value, 10, 64)
+            if err != nil {
+                continue
+            }
+            switch key {
+            case "rbytes":
+                read += n
+            case "wbytes":
+                write += n
+            }
+        }
+    }
+    return read, write
+}
+
+// 메모리 제한이 없을 때 기준이 되는 호스트 전체 메모리
+func hostMemoryTotal() uint64 {
+    data, err := os.ReadFile("/proc/meminfo")
+    if err != nil {
+        return 0
+    }
+    for _, line := range strings.Split(string(data), "\n") {
+        fields := strings.Fields(line)
+        if len(fields) >= 2 && fields[0] == "MemTotal:" {
+            kb, _ := strconv.ParseUint(fields[1], 10, 64)
+            return kb * 1024
+        }
+    }
+    return 0
+}
+
+func formatBytes(n uint64) string {
+    units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+    value := float64(n)
+    i := 0
+    for value >= 1024 && i < len(units)-1 {
+        value /= 1024
+        i++
+    }
+    if i == 0 {
+        return fmt.Sprintf("%d%s", n, units[0])
+    }
+    return fmt.Sprintf("%.2f%s", value, units[i])
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+// cgroup 파일 형식의 임시 파일 생성
+func writeStatFile(t *testing.T, content string) string {
+    t.Helper()
+    path := filepath.Join(t.TempDir(), "stat")
+    if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
+
+func TestReadKeyValueFile(t *testing.T) {
+    path := writeStatFile(t, "usage_usec 1500\nuser_usec 1000\nnr_throttled 0\nbroken\nsome text here\nnegative -1\n")
+    got, err := readKeyValueFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := map[string]uint64{"usage_usec": 1500, "user_usec": 1000, "nr_throttled": 0}
+    if !reflect.DeepEqual(got, want) {
+        t.Fatalf("readKeyValueFile() = %v, want %v", got, want)
+    }
+    if _, err := readKeyValueFile(path + ".missing"); err == nil {
+        t.Fatalf("readKeyValueFile() on a missing file succeeded")
+    }
+}
+
+func TestReadUintFile(t *testing.T) {
+    tests := []struct {
+        content string
+        want    uint64
+        wantErr bool
+    }{
+        {content: "1048576\n", want: 1048576},
+        {content: "max\n", want: 0},
+        {content: "0", want: 0},
+        {content: "12 34", wantErr: true},
+        {content: "", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.content, func(t *testing.T) {
+            got, err := readUintFile(writeStatFile(t, tt.content))
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("readUintFile(%q) = %d, want error", tt.content, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got != tt.want {
+                t.Fatalf("readUintFile(%q) = %d, want %d", tt.content, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestReadIOStat(t *testing.T) {
+    // 장치별 합계, 형식이 맞지 않는 필드는 무시
+    path := writeStatFile(t, "8:0 rbytes=4096 wbytes=1024 rios=1 wios=1 dbytes=0 dios=0\n"+
+        "8:16 rbytes=100 wbytes=x rios=3\n"+
+        "253:0 wbytes=2048\n")
+    read, write := readIOStat(path)
+    if read != 4196 || write != 3072 {
+        t.Fatalf("readIOStat() = %d, %d, want 4196, 3072", read, write)
+    }
+    if read, write := readIOStat(path + ".missing"); read != 0 || write != 0 {
+        t.Fatalf("readIOStat() on a missing file = %d, %d, want 0, 0", read, write)
+    }
+}
+
+func TestFormatBytes(t *testing.T) {
+    tests := []struct {
+        n    uint64
+        want string
+    }{
+        {0, "0B"},
+        {1023, "1023B"},
+        {1024, "1.00KiB"},
+        {1536, "1.50KiB"},
+        {10 * 1024 * 1024, "10.00MiB"},
+        {3 << 30, "3.00GiB"},
+        {5 << 40, "5.00TiB"},
+        {2048 << 40, "2048.00TiB"},
+    }
+
+    for _, tt := range tests {
+        if got := formatBytes(tt.n); got != tt.want {
+            t.Errorf("formatBytes(%d) = %s, want %s", tt.n, got, tt.want)
+        }
+    }
+}
@@ -5,91 +5,187 @@ import (
     "os"
     "os/exec"
     "path/filepath"
-    "strings"
+    "syscall"
+    "time"
 
     "github.com/spf13/cobra"
 )
 
+// 정지 신호를 보낸 뒤 강제 종료까지 기다리는 기본 시간
+const defaultStopTimeout = 10
+
+var (
+    stopTimeout int
+    stopSignal  string
+)
+
 var stopCmd = &cobra.Command{
-    Use:   "stop [containerName]",
+    Use:   "stop [container]",
     Short: "Stop a running container",
     Args:  cobra.ExactArgs(1),
     RunE: func(cmd *cobra.Command, args []string) error {
-        containerName := args[0]
-        
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+
         fmt.Println("     Container Stop    ")
         fmt.Println("=========================")
-        
-        return stopContainer(containerName)
+
+        return stopContainer(containerName, stopSignal, time.Duration(stopTimeout)*time.Second)
     },
 }
 
 func init() {
+    stopCmd.Flags().IntVarP(&stopTimeout, "time", "t", defaultStopTimeout, "Seconds to wait for the container to stop before killing it")
+    stopCmd.Flags().StringVarP(&stopSignal, "signal", "s", "", "Signal to send to the container (defaults to the image STOPSIGNAL or SIGTERM)")
     rootCmd.AddCommand(stopCmd)
 }
 
-func stopContainer(containerName string) error {
-    containerPath := "/CarteDaemon/container/" + containerName
-    pidFilePath := filepath.Join(containerPath, "pid")
-
-    // PID 파일에서 컨테이너의 PID 읽기
-    pid, err := os.ReadFile(pidFilePath)
+// 컨테이너 PID 1에 정지 신호를 보내고 timeout 동안 기다린 뒤, 남아 있으면 cgroup 전체를 SIGKILL
+// 프로세스가 모두 사라진 다음에 네트워크와 cgroup을 정리하고 종료 코드를 기록
+func stopContainer(containerName, signalName string, timeout time.Duration) error {
+    state, err := loadContainerState(containerName)
     if err != nil {
-        return fmt.Errorf("failed to read PID file for container %s: %v", containerName, err)
+        return err
     }
-    pidStr := strings.TrimSpace(string(pid))
-    fmt.Printf("Stopping container %s with PID %s\n", containerName, pidStr)
 
-    // 마운트 해제
-    if err := unmountContainer(containerPath); err != nil {
-        fmt.Printf("Warning: failed to unmount container %s: %v\n", containerName, err)
+    if state.isActive() {
+        sig, err := containerStopSignal(containerName, signalName)
+        if err != nil {
+            return err
+        }
+        fmt.Printf("Stopping container %s with PID %d (%s)\n", state.Name, state.Pid, unixSignalName(sig))
+
+        if err := syscall.Kill(state.Pid, sig); err != nil && err != syscall.ESRCH {
+            return fmt.Errorf("failed to send %s to container %s: %v", unixSignalName(sig), state.Name, err)
+        }
+        // 멈춘 프로세스는 신호를 처리하지 못하므로 보낸 뒤 풀어 줌
+        if state.Status == statusPaused {
+            if err := freezeCgroup(containerName, false); err != nil {
+                return err
+            }
+            setContainerStatus(containerName, statusRunning)
+        }
+        if !waitProcessExit(state.Pid, state.PidStartTime, timeout) {
+            fmt.Printf("Container %s did not stop within %s, killing it\n", state.Name, timeout)
+            sig = syscall.SIGKILL
+            syscall.Kill(state.Pid, sig)
+        }
+        if err := reapContainer(containerName, state.Pid, sig); err != nil {
+            return err
+        }
+        recordContainerEvent(containerName, "stop", map[string]string{"signal": unixSignalName(sig)})
     } else {
-        fmt.Printf("Unmounted all mounts for container %s\n", containerName)
+        fmt.Printf("Container %s is not running\n", state.Name)
     }
 
-    // veth 인터페이스 삭제
-    vethHost := fmt.Sprintf("vh_%s", pidStr)
-    if err := deleteVethInterface(vethHost); err != nil {
-        fmt.Printf("Warning: failed to delete veth interface %s: %v\n", vethHost, err)
-    } else {
-        fmt.Printf("Deleted veth interface %s\n", vethHost)
+    cleanupContainer(containerName)
+    return nil
+}
+
+// 사용할 정지 신호: --signal, 이미지의 STOPSIGNAL, SIGTERM 순
+func containerStopSignal(containerName, signalName string) (syscall.Signal, error) {
+    if signalName == "" {
+        if image, err := loadContainerImageConfig(containerName); err == nil {
+            signalName = image.StopSignal
+        }
     }
+    if signalName == "" {
+        return syscall.SIGTERM, nil
+    }
+    return parseSignal(signalName)
+}
 
-    // PID 파일 삭제
-    if err := os.Remove(pidFilePath); err != nil {
-        fmt.Printf("Warning: failed to remove PID file for container %s: %v\n", containerName, err)
-    } else {
-        fmt.Printf("Removed PID file for container %s\n", containerName)
+// 프로세스가 끝날 때까지 대기, timeout 안에 끝나면 true
+func waitProcessExit(pid int, startTime uint64, timeout time.Duration) bool {
+    deadline := time.Now().Add(timeout)
+    for processAlive(pid, startTime) {
+        if time.Now().After(deadline) {
+            return false
+        }
+        time.Sleep(100 * time.Millisecond)
     }
+    return true
+}
 
+// PID 1이 끝난 컨테이너의 남은 프로세스(exec 등)를 모두 종료하고 종료 코드 기록 (stop, kill 공통)
+func reapContainer(containerName string, pid int, sig syscall.Signal) error {
+    if err := killCgroup(containerName); err != nil {
+        return err
+    }
+    if err := waitCgroupEmpty(containerName, 10*time.Second); err != nil {
+        return err
+    }
+    recordStopExit(containerName, pid, sig)
     return nil
 }
 
-// veth 인터페이스 삭제 함수
-func deleteVethInterface(vethHost string) error {
-    if err := exec.Command("ip", "link", "del", vethHost).Run(); err != nil {
-        return fmt.Errorf("failed to delete veth interface %s: %v", vethHost, err)
+// 종료 코드는 컨테이너를 기다리는 shim(또는 포그라운드 start)이 기록함
+// 그 프로세스가 이미 없어 기록되지 않으면 보낸 신호로 끝난 것으로 기록
+func recordStopExit(containerName string, pid int, sig syscall.Signal) {
+    deadline := time.Now().Add(2 * time.Second)
+    for time.Now().Before(deadline) {
+        if state, err := readContainerState(containerName); err == nil && !(state.isActive() && state.Pid == pid) {
+            fmt.Printf("Container %s exited with code %d\n", state.Name, state.ExitCode)
+            return
+        }
+        time.Sleep(100 * time.Millisecond)
     }
-    return nil
+
+    exitCode := 128 + int(sig)
+    if err := markContainerExited(containerName, exitCode, false); err != nil {
+        fmt.Printf("Warning: failed to update state of container %s: %v\n", containerName, err)
+        return
+    }
+    fmt.Printf("Container %s exited with code %d\n", containerName, exitCode)
 }
 
-// 마운트 해제 함수
-func unmountContainer(containerPath string) error {
-    // 컨테이너의 루트 및 시스템 디렉토리 포함
-    mountPoints := []string{
-        filepath.Join(containerPath, "proc"),
-        filepath.Join(containerPath, "sys"),
-        filepath.Join(containerPath, "dev"),
-        containerPath, // 루트 컨테이너 경로
+// PID 1이 끝난 컨테이너에 남은 프로세스를 종료한 뒤 네트워크와 cgroup 정리 (컨테이너가 스스로 끝났을 때)
+func releaseContainer(containerName string) {
+    if err := killCgroup(containerName); err != nil {
+        fmt.Printf("Warning: %v\n", err)
+    } else if err := waitCgroupEmpty(containerName, 10*time.Second); err != nil {
+        fmt.Printf("Warning: %v\n", err)
     }
+    cleanupContainer(containerName)
+}
 
-    for _, mountPoint := range mountPoints {
-        if err := exec.Command("umount", "-l", mountPoint).Run(); err != nil {
-            fmt.Printf("Warning: failed to unmount %s: %v\n", mountPoint, err)
-            continue // 오류가 발생해도 다음 마운트 포인트로 넘어감
+// 종료된 컨테이너의 veth, 네트워크 네임스페이스 링크, 서브넷 예약, cgroup 정리
+func cleanupContainer(containerName string) {
+    // veth 인터페이스와 네트워크 네임스페이스 링크 삭제
+    // (네임스페이스가 사라질 때 veth도 함께 지워지므로 이미 없을 수 있음)
+    if network, err := loadContainerNetwork(containerName); err == nil {
+        vethPath := filepath.Join("/sys/class/net", network.HostVeth)
+        if _, err := os.Stat(vethPath); err == nil {
+            if err := deleteVethInterface(network.HostVeth); err == nil {
+                fmt.Printf("Deleted veth interface %s\n", network.HostVeth)
+            } else if _, statErr := os.Stat(vethPath); !os.IsNotExist(statErr) {
+                // 확인한 뒤 삭제하기 전에 네임스페이스와 함께 사라진 경우는 경고하지 않음
+                fmt.Printf("Warning: failed to delete veth interface %s: %v\n", network.HostVeth, err)
+            }
         }
-        fmt.Printf("Unmounted %s\n", mountPoint)
+        if err := os.Remove(filepath.Join("/run/netns", network.Netns)); err != nil && !os.IsNotExist(err) {
+            fmt.Printf("Warning: failed to remove netns link %s: %v\n", network.Netns, err)
+        }
+        releaseContainerNetwork(containerName)
+    }
+
+    // cgroup 삭제 (이미 정리된 경우 출력 없음)
+    if !cgroupExists(containerName) {
+        return
     }
+    if err := removeCgroup(containerName); err != nil {
+        fmt.Printf("Warning: %v\n", err)
+    } else {
+        fmt.Printf("Removed cgroup for container %s\n", containerName)
+    }
+}
 
+// veth 인터페이스 삭제 함수
+func deleteVethInterface(vethHost string) error {
+    if err := exec.Command("ip", "link", "del", vethHost).Run(); err != nil {
+        return fmt.Errorf("failed to delete veth interface %s: %v", vethHost, err)
+    }
     return nil
 }
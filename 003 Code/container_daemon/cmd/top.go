@@ -0,0 +1,325 @@
+package cmd
+
+import (
+    "bufio"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "text/tabwriter"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+// /proc의 시간 단위 (리눅스에서 사용자 공간에 보이는 USER_HZ는 항상 100)
+const clockTicksPerSecond = 100
+
+var topCmd = &cobra.Command{
+    Use:   "top [container] [ps-options]",
+    Short: "Display the processes running in a container",
+    Long: `Display the processes running in a container with both the host PID and the PID inside the container.
+
+Supported ps options: -o/--format <columns> (comma separated, may be repeated),
+-f (full format), u (user format); -e, -A, a and x are accepted and ignored because
+every process in the container is always listed.
+Columns: ` + strings.Join(topColumnNames(), ", "),
+    Args: cobra.MinimumNArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        containerName, err := resolveContainer(args[0])
+        if err != nil {
+            return err
+        }
+        columns, err := parseTopOptions(args[1:])
+        if err != nil {
+            return err
+        }
+        return topContainer(containerName, columns)
+    },
+}
+
+func init() {
+    // 컨테이너 이름 뒤의 플래그는 ps 옵션으로 해석
+    topCmd.Flags().SetInterspersed(false)
+    rootCmd.AddCommand(topCmd)
+}
+
+// topProcess : /proc/<pid>의 stat, status, cmdline에서 읽은 프로세스 정보
+type topProcess struct {
+    Pid       int
+    NSpid     int // 컨테이너 PID 네임스페이스 안의 PID (NSpid의 마지막 값)
+    Ppid      int
+    Uid       int // effective uid
+    State     string
+    Threads   string
+    CPUTicks  uint64 // utime + stime
+    StartTick uint64 // 부팅 후 시작 시각
+    RSS       uint64 // 바이트
+    VSZ       uint64 // 바이트
+    Comm      string
+    Cmdline   string
+}
+
+type topColumn struct {
+    header string
+    value  func(p *topProcess, ctx *topContext) string
+}
+
+// 출력할 때 필요한 컨테이너 단위 정보
+type topContext struct {
+    users    map[int]string // 컨테이너 /etc/passwd 기준
+    bootTime time.Time
+}
+
+var topColumns = map[string]topColumn{
+    "pid":   {"PID", func(p *topProcess, _ *topContext) string { return strconv.Itoa(p.Pid) }},
+    "nspid": {"NSPID", func(p *topProcess, _ *topContext) string { return strconv.Itoa(p.NSpid) }},
+    "ppid":  {"PPID", func(p *topProcess, _ *topContext) string { return strconv.Itoa(p.Ppid) }},
+    "uid":   {"UID", func(p *topProcess, _ *topContext) string { return strconv.Itoa(p.Uid) }},
+    "user":  {"USER", func(p *topProcess, c *topContext) string { return c.userName(p.Uid) }},
+    "stat":  {"STAT", func(p *topProcess, _ *topContext) string { return p.State }},
+    "nlwp":  {"NLWP", func(p *topProcess, _ *topContext) string { return p.Threads }},
+    "time":  {"TIME", func(p *topProcess, _ *topContext) string { return formatCPUTime(p.CPUTicks) }},
+    "stime": {"STIME", func(p *topProcess, c *topContext) string { return c.formatStartTime(p.StartTick) }},
+    "rss":   {"RSS", func(p *topProcess, _ *topContext) string { return formatBytes(p.RSS) }},
+    "vsz":   {"VSZ", func(p *topProcess, _ *topContext) string { return formatBytes(p.VSZ) }},
+    "comm":  {"COMMAND", func(p *topProcess, _ *topContext) string { return p.Comm }},
+    "cmd":   {"CMD", func(p *topProcess, _ *topContext) string { return p.Cmdline }},
+}
+
+// ps 옵션 이름의 별칭
+var topColumnAliases = map[string]string{
+    "args":    "cmd",
+    "command": "cmd",
+    "ucmd":    "comm",
+    "euid":    "uid",
+    "euser":   "user",
+    "s":       "stat",
+    "state":   "stat",
+    "thcount": "nlwp",
+    "start":   "stime",
+    "cputime": "time",
+    "rssize":  "rss",
+    "vsize":   "vsz",
+}
+
+var (
+    topDefaultColumns = []string{"pid", "nspid", "ppid", "user", "stat", "stime", "time", "cmd"}
+    topFullColumns    = []string{"user", "pid", "nspid", "ppid", "nlwp", "stime", "time", "cmd"}
+    topUserColumns    = []string{"user", "pid", "nspid", "vsz", "rss", "stat", "stime", "time", "cmd"}
+)
+
+func topColumnNames() []string {
+    names := make([]string, 0, len(topColumns))
+    for name := range topColumns {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// ps 옵션 중 열 선택에 관련된 것만 해석 ("-ef", "aux", "-o pid,cmd", "-opid", "--format=pid")
+func parseTopOptions(args []string) ([]string, error) {
+    var selected []string
+    preset := topDefaultColumns
+    for i := 0; i < len(args); i++ {
+        arg := args[i]
+        var list string
+        switch {
+        case arg == "-o" || arg == "o" || arg == "--format":
+            if i+1 >= len(args) {
+                return nil, fmt.Errorf("option %s requires a column list", arg)
+            }
+            i++
+            list = args[i]
+        case strings.HasPrefix(arg, "--format="):
+            list = strings.TrimPrefix(arg, "--format=")
+        case strings.HasPrefix(arg, "-o"):
+            list = strings.TrimPrefix(arg, "-o")
+        default:
+            for _, r := range strings.TrimPrefix(arg, "-") {
+                switch r {
+                case 'e', 'A', 'a', 'x':
+                case 'f':
+                    preset = topFullColumns
+                case 'u':
+                    preset = topUserColumns
+                default:
+                    return nil, fmt.Errorf("unsupported ps option %q in %q", r, arg)
+                }
+            }
+            continue
+        }
+
+        for _, name := range strings.Split(list, ",") {
+            name = strings.ToLower(strings.TrimSpace(name))
+            if alias, ok := topColumnAliases[name]; ok {
+                name = alias
+            }
+            if _, ok := topColumns[name]; !ok {
+                return nil, fmt.Errorf("unknown column %q, expected one of %s", name, strings.Join(topColumnNames(), ", "))
+            }
+            selected = append(selected, name)
+        }
+    }
+    if selected != nil {
+        return selected, nil
+    }
+    return preset, nil
+}
+
+// 컨테이너 cgroup의 프로세스를 호스트 PID 순으로 출력
+func topContainer(containerName string, columns []string) error {
+    state, err := loadContainerState(containerName)
+    if err != nil {
+        return err
+    }
+    if !state.isActive() {
+        return fmt.Errorf("container %s is not running", state.Name)
+    }
+    pids, err := readCgroupProcs(containerName)
+    if err != nil {
+        return err
+    }
+    sort.Ints(pids)
+
+    ctx := &topContext{users: map[int]string{}, bootTime: readBootTime()}
+    passwd, _ := readContainerDB(filepath.Join("/CarteDaemon/container", containerName), "/etc/passwd")
+    for _, fields := range passwd {
+        if len(fields) >= 3 {
+            if uid, err := strconv.Atoi(fields[2]); err == nil {
+                if _, ok := ctx.users[uid]; !ok {
+                    ctx.users[uid] = fields[0]
+                }
+            }
+        }
+    }
+
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+    headers := make([]string, len(columns))
+    for i, name := range columns {
+        headers[i] = topColumns[name].header
+    }
+    fmt.Fprintln(w, strings.Join(headers, "\t"))
+    for _, pid := range pids {
+        process, err := readTopProcess(pid)
+        if err != nil {
+            continue // 목록을 읽은 뒤 종료된 프로세스
+        }
+        values := make([]string, len(columns))
+        for i, name := range columns {
+            values[i] = topColumns[name].value(process, ctx)
+        }
+        fmt.Fprintln(w, strings.Join(values, "\t"))
+    }
+    return w.Flush()
+}
+
+func readTopProcess(pid int) (*topProcess, error) {
+    fields, err := readProcStat(pid)
+    if err != nil {
+        return nil, err
+    }
+    p := &topProcess{Pid: pid, NSpid: pid, State: fields[0], Threads: fields[17]}
+    p.Ppid, _ = strconv.Atoi(fields[1])
+    utime, _ := strconv.ParseUint(fields[11], 10, 64)
+    stime, _ := strconv.ParseUint(fields[12], 10, 64)
+    p.CPUTicks = utime + stime
+    p.StartTick, _ = strconv.ParseUint(fields[19], 10, 64)
+    p.VSZ, _ = strconv.ParseUint(fields[20], 10, 64)
+    if rssPages, err := strconv.ParseUint(fields[21], 10, 64); err == nil {
+        p.RSS = rssPages * uint64(os.Getpagesize())
+    }
+
+    status, err := readProcStatus(pid)
+    if err != nil {
+        return nil, err
+    }
+    // Uid: real effective saved fs
+    if uids := status["Uid"]; len(uids) >= 2 {
+        p.Uid, _ = strconv.Atoi(uids[1])
+    }
+    // NSpid: 바깥 네임스페이스부터 안쪽 순서, 마지막이 컨테이너 안의 PID
+    if nspids := status["NSpid"]; len(nspids) > 0 {
+        p.NSpid, _ = strconv.Atoi(nspids[len(nspids)-1])
+    }
+
+    comm, _ := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+    p.Comm = strings.TrimSpace(string(comm))
+    cmdline, _ := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+    p.Cmdline = strings.TrimSpace(strings.ReplaceAll(strings.TrimRight(string(cmdline), "\x00"), "\x00", " "))
+    if p.Cmdline == "" {
+        p.Cmdline = "[" + p.Comm + "]" // 좀비 등 인자가 없는 프로세스
+    }
+    return p, nil
+}
+
+// /proc/<pid>/status의 "키:\t값..." 줄을 필드 목록으로
+func readProcStatus(pid int) (map[string][]string, error) {
+    file, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+
+    status := map[string][]string{}
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        key, value, ok := strings.Cut(scanner.Text(), ":")
+        if ok {
+            status[key] = strings.Fields(value)
+        }
+    }
+    return status, scanner.Err()
+}
+
+// /proc/stat의 btime (부팅 시각, 유닉스 초)
+func readBootTime() time.Time {
+    file, err := os.Open("/proc/stat")
+    if err != nil {
+        return time.Time{}
+    }
+    defer file.Close()
+    scanner := bufio.NewScanner(file)
+    for scanner.Scan() {
+        if value, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+            if sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+                return time.Unix(sec, 0)
+            }
+        }
+    }
+    return time.Time{}
+}
+
+func (c *topContext) userName(uid int) string {
+    if name, ok := c.users[uid]; ok {
+        return name
+    }
+    return strconv.Itoa(uid)
+}
+
+// ps의 STIME 형식: 오늘 시작했으면 시:분, 아니면 월일
+func (c *topContext) formatStartTime(ticks uint64) string {
+    if c.bootTime.IsZero() {
+        return "-"
+    }
+    start := c.bootTime.Add(time.Duration(ticks) * time.Second / clockTicksPerSecond)
+    now := time.Now()
+    if start.YearDay() == now.YearDay() && start.Year() == now.Year() {
+        return start.Format("15:04")
+    }
+    return start.Format("Jan02")
+}
+
+// ps의 TIME 형식: [일-]시:분:초
+func formatCPUTime(ticks uint64) string {
+    seconds := ticks / clockTicksPerSecond
+    days, seconds := seconds/86400, seconds%86400
+    clock := fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+    if days > 0 {
+        return fmt.Sprintf("%d-%s", days, clock)
+    }
+    return clock
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+    "os"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestParseTopOptions(t *testing.T) {
+    tests := []struct {
+        name    string
+        args    []string
+        want    []string
+        wantErr bool
+    }{
+        {name: "default", want: topDefaultColumns},
+        {name: "ignored selectors", args: []string{"-e", "ax", "-A"}, want: topDefaultColumns},
+        {name: "full", args: []string{"-ef"}, want: topFullColumns},
+        {name: "user", args: []string{"aux"}, want: topUserColumns},
+        {name: "column list", args: []string{"-o", "pid,cmd"}, want: []string{"pid", "cmd"}},
+        {name: "attached list", args: []string{"-opid,comm"}, want: []string{"pid", "comm"}},
+        {name: "bsd o", args: []string{"o", "pid"}, want: []string{"pid"}},
+        {name: "format", args: []string{"--format=PID, User"}, want: []string{"pid", "user"}},
+        {name: "repeated lists", args: []string{"-o", "pid", "--format", "ppid"}, want: []string{"pid", "ppid"}},
+        {name: "aliases", args: []string{"-o", "args,ucmd,euser,state,vsize"}, want: []string{"cmd", "comm", "user", "stat", "vsz"}},
+        // 열을 직접 고르면 -f 등의 기본 묶음은 무시
+        {name: "list overrides preset", args: []string{"-ef", "-o", "pid"}, want: []string{"pid"}},
+        {name: "missing list", args: []string{"-o"}, wantErr: true},
+        {name: "unknown column", args: []string{"-o", "pid,pcpu"}, wantErr: true},
+        {name: "empty column", args: []string{"-o", "pid,"}, wantErr: true},
+        {name: "unsupported option", args: []string{"-ejH"}, wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := parseTopOptions(tt.args)
+            if (err != nil) != tt.wantErr {
+                t.Fatalf("parseTopOptions(%q) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+            }
+            if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+                t.Fatalf("parseTopOptions(%q) = %q, want %q", tt.args, got, tt.want)
+            }
+        })
+    }
+}
+
+// 기본 묶음과 별칭은 모두 실제 열을 가리켜야 함
+func TestTopColumnSets(t *testing.T) {
+    for _, set := range [][]string{topDefaultColumns, topFullColumns, topUserColumns} {
+        for _, name := range set {
+            if _, ok := topColumns[name]; !ok {
+                t.Fatalf("preset column %q is not defined", name)
+            }
+        }
+    }
+    for alias, name := range topColumnAliases {
+        if _, ok := topColumns[name]; !ok {
+            t.Fatalf("alias %q points to undefined column %q", alias, name)
+        }
+    }
+}
+
+func TestFormatCPUTime(t *testing.T) {
+    tests := []struct {
+        ticks uint64
+        want  string
+    }{
+        {ticks: 0, want: "00:00:00"},
+        {ticks: 99, want: "00:00:00"},
+        {ticks: 100, want: "00:00:01"},
+        {ticks: 61 * 100, want: "00:01:01"},
+        {ticks: 3723 * 100, want: "01:02:03"},
+        {ticks: (86400 + 3600) * 100, want: "1-01:00:00"},
+    }
+
+    for _, tt := range tests {
+        if got := formatCPUTime(tt.ticks); got != tt.want {
+            t.Fatalf("formatCPUTime(%d) = %q, want %q", tt.ticks, got, tt.want)
+        }
+    }
+}
+
+func TestTopContext(t *testing.T) {
+    ctx := &topContext{users: map[int]string{0: "root", 33: "www-data"}}
+    if got := ctx.userName(33); got != "www-data" {
+        t.Fatalf("userName(33) = %q, want \"www-data\"", got)
+    }
+    if got := ctx.userName(1000); got != "1000" {
+        t.Fatalf("userName(1000) = %q, want \"1000\"", got)
+    }
+
+    if got := ctx.formatStartTime(100); got != "-" {
+        t.Fatalf("formatStartTime() without boot time = %q, want \"-\"", got)
+    }
+    ctx.bootTime = time.Now().Add(-time.Minute)
+    start := ctx.bootTime.Add(30 * time.Second)
+    // 자정 직후에는 시작 시각이 어제일 수 있으므로 오늘인 경우만 확인
+    if start.YearDay() == time.Now().YearDay() {
+        if got, want := ctx.formatStartTime(30*clockTicksPerSecond), start.Format("15:04"); got != want {
+            t.Fatalf("formatStartTime() today = %q, want %q", got, want)
+        }
+    }
+    ctx.bootTime = time.Date(2020, time.March, 5, 10, 0, 0, 0, time.Local)
+    if got := ctx.formatStartTime(0); got != "Mar05" {
+        t.Fatalf("formatStartTime() on an earlier day = %q, want \"Mar05\"", got)
+    }
+}
+
+func TestReadTopProcess(t *testing.T) {
+    pid := os.Getpid()
+    p, err := readTopProcess(pid)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if p.Pid != pid || p.Ppid != os.Getppid() || p.Uid != os.Geteuid() {
+        t.Fatalf("readTopProcess(self) = pid %d, ppid %d, uid %d, want %d, %d, %d", p.Pid, p.Ppid, p.Uid, pid, os.Getppid(), os.Geteuid())
+    }
+    if p.Comm == "" || !strings.Contains(p.Cmdline, os.Args[0]) {
+        t.Fatalf("readTopProcess(self) comm %q, cmdline %q, want the test binary", p.Comm, p.Cmdline)
+    }
+    if p.RSS == 0 || p.VSZ == 0 {
+        t.Fatalf("readTopProcess(self) rss %d, vsz %d, want non-zero", p.RSS, p.VSZ)
+    }
+}
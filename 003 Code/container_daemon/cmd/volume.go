@@ -0,0 +1,315 @@
+package cmd
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "regexp"
+    "strings"
+    "syscall"
+    "time"
+
+    "github.com/spf13/cobra"
+)
+
+const volumeRoot = "/CarteDaemon/volume"
+
+var volumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// volumeInfo : 이름 있는 볼륨의 메타데이터 (/CarteDaemon/volume/<name>/volume.json)
+type volumeInfo struct {
+    Name       string    `json:"name"`
+    Mountpoint string    `json:"mountpoint"`
+    CreatedAt  time.Time `json:"createdAt"`
+}
+
+// volumeMount : 컨테이너에 연결할 바인드 마운트 또는 볼륨 (-v)
+type volumeMount struct {
+    Source   string `json:"source"`           // 호스트 경로
+    Target   string `json:"target"`           // 컨테이너 내부 경로
+    Volume   string `json:"volume,omitempty"` // 이름 있는 볼륨인 경우 볼륨 이름
+    ReadOnly bool   `json:"readOnly"`
+
+    // 실제로 마운트된 호스트 쪽 경로 (컨테이너 루트 안에서 해석한 결과)
+    MountPoint string `json:"mountPoint,omitempty"`
+}
+
+var volumeCmd = &cobra.Command{
+    Use:   "volume",
+    Short: "Manage volumes",
+}
+
+var volumeCreateCmd = &cobra.Command{
+    Use:   "create [volumeName]",
+    Short: "Create a volume",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        volume, err := createVolume(args[0])
+        if err != nil {
+            return err
+        }
+        fmt.Println(volume.Name)
+        return nil
+    },
+}
+
+var volumeListCmd = &cobra.Command{
+    Use:   "ls",
+    Short: "List volumes",
+    RunE: func(cmd *cobra.Command, args []string) error {
+        return listVolumes()
+    },
+}
+
+var volumeInspectCmd = &cobra.Command{
+    Use:   "inspect [volumeName]",
+    Short: "Display detailed information on a volume",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        volume, err := loadVolume(args[0])
+        if err != nil {
+            return err
+        }
+        data, err := json.MarshalIndent(volume, "", "  ")
+        if err != nil {
+            return err
+        }
+        fmt.Println(string(data))
+        return nil
+    },
+}
+
+var volumeRemoveCmd = &cobra.Command{
+    Use:   "rm [volumeName]",
+    Short: "Remove a volume",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+        return removeVolume(args[0])
+    },
+}
+
+func init() {
+    volumeCmd.AddCommand(volumeCreateCmd, volumeListCmd, volumeInspectCmd, volumeRemoveCmd)
+    rootCmd.AddCommand(volumeCmd)
+}
+
+func createVolume(name string) (*volumeInfo, error) {
+    if !volumeNamePattern.MatchString(name) {
+        return nil, fmt.Errorf("invalid volume name %q", name)
+    }
+
+    if volume, err := loadVolume(name); err == nil {
+        return volume, nil // 이미 있으면 그대로 사용
+    }
+
+    volumeDir := filepath.Join(volumeRoot, name)
+    volume := &volumeInfo{
+        Name:       name,
+        Mountpoint: filepath.Join(volumeDir, "_data"),
+        CreatedAt:  time.Now(),
+    }
+    if err := os.MkdirAll(volume.Mountpoint, 0755); err != nil {
+        return nil, fmt.Errorf("failed to create volume directory: %v", err)
+    }
+
+    data, err := json.MarshalIndent(volume, "", "  ")
+    if err != nil {
+        return nil, err
+    }
+    if err := os.WriteFile(filepath.Join(volumeDir, "volume.json"), data, 0644); err != nil {
+        return nil, fmt.Errorf("failed to write volume metadata: %v", err)
+    }
+    return volume, nil
+}
+
+func loadVolume(name string) (*volumeInfo, error) {
+    // 이름이 경로로 쓰이므로 volumeRoot 밖을 가리키지 않도록 먼저 검사 (inspect, rm 공통)
+    if !volumeNamePattern.MatchString(name) {
+        return nil, fmt.Errorf("invalid volume name %q", name)
+    }
+    data, err := os.ReadFile(filepath.Join(volumeRoot, name, "volume.json"))
+    if os.IsNotExist(err) {
+        return nil, fmt.Errorf("volume %s does not exist", name)
+    }
+    if err != nil {
+        return nil, fmt.Errorf("failed to read volume %s: %v", name, err)
+    }
+
+    var volume volumeInfo
+    if err := json.Unmarshal(data, &volume); err != nil {
+        return nil, fmt.Errorf("failed to parse volume %s: %v", name, err)
+    }
+    return &volume, nil
+}
+
+func listVolumes() error {
+    files, err := os.ReadDir(volumeRoot)
+    if err != nil && !os.IsNotExist(err) {
+        return fmt.Errorf("failed to read volume directory: %v", err)
+    }
+
+    fmt.Println("       Volume List       ")
+    fmt.Println("=========================")
+
+    for _, file := range files {
+        if !file.IsDir() {
+            continue
+        }
+        volume, err := loadVolume(file.Name())
+        if err != nil {
+            continue
+        }
+        fmt.Printf("%s   %s\n", volume.Name, volume.Mountpoint)
+    }
+    return nil
+}
+
+func removeVolume(name string) error {
+    if _, err := loadVolume(name); err != nil {
+        return err
+    }
+
+    // 실행 중인 컨테이너가 사용 중이면 삭제하지 않음
+    files, _ := os.ReadDir("/CarteDaemon/container")
+    for _, file := range files {
+        if !file.IsDir() {
+            continue
+        }
+        if running, err := isContainerRunning(file.Name()); err != nil || !running {
+            continue
+        }
+        mounts, _ := loadContainerMounts(file.Name())
+        for _, mount := range mounts {
+            if mount.Volume == name {
+                return fmt.Errorf("volume %s is in use by container %s", name, file.Name())
+            }
+        }
+    }
+
+    if err := os.RemoveAll(filepath.Join(volumeRoot, name)); err != nil {
+        return fmt.Errorf("failed to remove volume %s: %v", name, err)
+    }
+    fmt.Printf("Volume %s removed successfully\n", name)
+    return nil
+}
+
+// -v 옵션 해석: hostPath:ctrPath[:ro] 또는 volumeName:ctrPath[:ro]
+func parseVolumeSpec(spec string) (volumeMount, error) {
+    var mount volumeMount
+
+    parts := strings.Split(spec, ":")
+    if len(parts) == 3 {
+        switch parts[2] {
+        case "ro":
+            mount.ReadOnly = true
+        case "rw":
+        default:
+            return mount, fmt.Errorf("invalid volume option %q in %s", parts[2], spec)
+        }
+        parts = parts[:2]
+    }
+    if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+        return mount, fmt.Errorf("invalid volume %q, expected hostPath:ctrPath[:ro] or name:ctrPath[:ro]", spec)
+    }
+    if !filepath.IsAbs(parts[1]) {
+        return mount, fmt.Errorf("container path %q must be absolute", parts[1])
+    }
+    mount.Target = filepath.Clean(parts[1])
+
+    // '/'가 없으면 이름 있는 볼륨 (없으면 새로 생성)
+    if !strings.Contains(parts[0], "/") {
+        volume, err := createVolume(parts[0])
+        if err != nil {
+            return mount, err
+        }
+        mount.Volume = volume.Name
+        mount.Source = volume.Mountpoint
+        return mount, nil
+    }
+
+    source, err := filepath.Abs(parts[0])
+    if err != nil {
+        return mount, err
+    }
+    if _, err := os.Stat(source); err != nil {
+        return mount, fmt.Errorf("bind source %s: %v", source, err)
+    }
+    mount.Source = source
+    return mount, nil
+}
+
+// 컨테이너에 연결된 마운트 목록 (메타데이터 디렉토리의 mounts.json, 사용 중인 볼륨 확인에 사용)
+func containerMountsPath(containerName string) string {
+    return filepath.Join(containerMetaDir(containerName), "mounts.json")
+}
+
+func loadContainerMounts(containerName string) ([]volumeMount, error) {
+    data, err := os.ReadFile(containerMountsPath(containerName))
+    if err != nil {
+        return nil, err
+    }
+    var mounts []volumeMount
+    if err := json.Unmarshal(data, &mounts); err != nil {
+        return nil, err
+    }
+    return mounts, nil
+}
+
+func saveContainerMounts(containerName string, mounts []volumeMount) error {
+    if err := os.MkdirAll(containerMetaDir(containerName), 0700); err != nil {
+        return fmt.Errorf("failed to create container meta directory: %v", err)
+    }
+    data, err := json.MarshalIndent(mounts, "", "  ")
+    if err != nil {
+        return err
+    }
+    return os.WriteFile(containerMountsPath(containerName), data, 0644)
+}
+
+// 마운트 대상을 컨테이너 루트 안에서 해석하여 기록 (실제 마운트는 nsinit이 수행)
+func resolveVolumeMounts(containerPath, containerName string, mounts []volumeMount) error {
+    for i := range mounts {
+        mountPoint, err := resolveInRoot(containerPath, mounts[i].Target)
+        if err != nil {
+            return fmt.Errorf("failed to resolve %s in container: %v", mounts[i].Target, err)
+        }
+        mounts[i].MountPoint = mountPoint
+    }
+    return saveContainerMounts(containerName, mounts)
+}
+
+// 컨테이너 루트 전환 전에 바인드 마운트와 볼륨을 연결 (컨테이너 마운트 네임스페이스 안)
+func mountVolumes(mounts []volumeMount) error {
+    for _, mount := range mounts {
+        info, err := os.Stat(mount.Source)
+        if err != nil {
+            return fmt.Errorf("bind source %s: %v", mount.Source, err)
+        }
+
+        // 마운트 대상이 없으면 원본과 같은 종류(디렉토리/파일)로 생성
+        if info.IsDir() {
+            if err := os.MkdirAll(mount.MountPoint, 0755); err != nil {
+                return fmt.Errorf("failed to create mount point %s: %v", mount.Target, err)
+            }
+        } else {
+            if err := os.MkdirAll(filepath.Dir(mount.MountPoint), 0755); err != nil {
+                return fmt.Errorf("failed to create mount point %s: %v", mount.Target, err)
+            }
+            if file, err := os.OpenFile(mount.MountPoint, os.O_CREATE, 0644); err == nil {
+                file.Close()
+            }
+        }
+
+        if err := syscall.Mount(mount.Source, mount.MountPoint, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+            return fmt.Errorf("failed to bind mount %s to %s: %v", mount.Source, mount.Target, err)
+        }
+        if mount.ReadOnly {
+            if err := syscall.Mount("", mount.MountPoint, "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY|syscall.MS_REC, ""); err != nil {
+                return fmt.Errorf("failed to remount %s read-only: %v", mount.Target, err)
+            }
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestParseVolumeSpecBind(t *testing.T) {
+    source := t.TempDir()
+    if err := os.Mkdir(filepath.Join(source, "data"), 0755); err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        spec    string
+        want    volumeMount
+        wantErr bool
+    }{
+        {spec: source + ":/data", want: volumeMount{Source: source, Target: "/data"}},
+        {spec: source + ":/data:ro", want: volumeMount{Source: source, Target: "/data", ReadOnly: true}},
+        {spec: source + ":/data:rw", want: volumeMount{Source: source, Target: "/data"}},
+        {spec: source + "/data/../data:/var//lib/", want: volumeMount{Source: filepath.Join(source, "data"), Target: "/var/lib"}},
+        {spec: source + ":/data:rx", wantErr: true},
+        {spec: source + ":data", wantErr: true},
+        {spec: source, wantErr: true},
+        {spec: ":/data", wantErr: true},
+        {spec: source + ":", wantErr: true},
+        {spec: source + ":/a:/b:ro", wantErr: true},
+        {spec: filepath.Join(source, "missing") + ":/data", wantErr: true},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.spec, func(t *testing.T) {
+            got, err := parseVolumeSpec(tt.spec)
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("parseVolumeSpec(%q) = %+v, want error", tt.spec, got)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatalf("parseVolumeSpec(%q) error = %v", tt.spec, err)
+            }
+            if got != tt.want {
+                t.Fatalf("parseVolumeSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+            }
+        })
+    }
+}